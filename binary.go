@@ -0,0 +1,424 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// Байты-метки видов тренировок в двоичном представлении.
+const (
+	binaryTagRunning byte = iota + 1
+	binaryTagWalking
+	binaryTagNordicWalking
+	binaryTagSwimming
+	binaryTagStationaryBike
+	binaryTagStrength
+)
+
+// ErrUnknownBinaryTag ошибка для двоичного представления с неизвестной или неподходящей меткой вида тренировки.
+var ErrUnknownBinaryTag = errors.New("неизвестная метка вида тренировки")
+
+// binaryWriter записывает поля фиксированной длины в порядке big-endian.
+type binaryWriter struct {
+	buf bytes.Buffer
+}
+
+// int64 записывает целое число.
+func (w *binaryWriter) int64(v int64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(v))
+	w.buf.Write(b[:])
+}
+
+// float64 записывает число с плавающей точкой.
+func (w *binaryWriter) float64(v float64) {
+	w.int64(int64(math.Float64bits(v)))
+}
+
+// bool записывает логическое значение одним байтом.
+func (w *binaryWriter) bool(v bool) {
+	if v {
+		w.buf.WriteByte(1)
+		return
+	}
+	w.buf.WriteByte(0)
+}
+
+// string записывает длину строки (uint32) и ее байты.
+func (w *binaryWriter) string(v string) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(len(v)))
+	w.buf.Write(b[:])
+	w.buf.WriteString(v)
+}
+
+// time записывает время в наносекундах Unix, нулевое время записывается как 0.
+func (w *binaryWriter) time(v time.Time) {
+	if v.IsZero() {
+		w.int64(0)
+		return
+	}
+	w.int64(v.UnixNano())
+}
+
+// training записывает общие поля тренировки.
+func (w *binaryWriter) training(t Training) {
+	w.string(t.TrainingType)
+	w.int64(int64(t.Action))
+	w.float64(t.LenStep)
+	w.int64(int64(t.Duration))
+	w.float64(t.Weight)
+	w.time(t.StartedAt)
+	w.float64(t.ElevationGain)
+	w.int64(int64(len(t.Tags)))
+	for _, tag := range t.Tags {
+		w.string(tag)
+	}
+	w.int64(int64(t.AvgHeartRate))
+	w.float64(t.BMR)
+	w.float64(t.FatigueFactor)
+	w.string(t.Notes)
+}
+
+// binaryReader читает поля, записанные binaryWriter, и запоминает первую ошибку.
+type binaryReader struct {
+	r   *bytes.Reader
+	err error
+}
+
+// newBinaryReader возвращает binaryReader для чтения data.
+func newBinaryReader(data []byte) *binaryReader {
+	return &binaryReader{r: bytes.NewReader(data)}
+}
+
+// read читает n байт. После первой ошибки возвращает нулевые байты.
+func (r *binaryReader) read(n int) []byte {
+	if r.err != nil {
+		return make([]byte, n)
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		r.err = fmt.Errorf("двоичное представление тренировки обрезано: %w", err)
+	}
+
+	return b
+}
+
+// int64 читает целое число.
+func (r *binaryReader) int64() int64 {
+	return int64(binary.BigEndian.Uint64(r.read(8)))
+}
+
+// float64 читает число с плавающей точкой.
+func (r *binaryReader) float64() float64 {
+	return math.Float64frombits(uint64(r.int64()))
+}
+
+// bool читает логическое значение.
+func (r *binaryReader) bool() bool {
+	return r.read(1)[0] != 0
+}
+
+// string читает строку, записанную binaryWriter.string.
+func (r *binaryReader) string() string {
+	length := binary.BigEndian.Uint32(r.read(4))
+	if r.err == nil && int64(length) > int64(r.r.Len()) {
+		r.err = errors.New("двоичное представление тренировки обрезано")
+		return ""
+	}
+
+	return string(r.read(int(length)))
+}
+
+// time читает время, записанное binaryWriter.time.
+func (r *binaryReader) time() time.Time {
+	nanoseconds := r.int64()
+	if nanoseconds == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, nanoseconds)
+}
+
+// tag читает метку вида тренировки и проверяет, что она равна expected.
+func (r *binaryReader) tag(expected byte) {
+	if tag := r.read(1)[0]; r.err == nil && tag != expected {
+		r.err = fmt.Errorf("%w: %d", ErrUnknownBinaryTag, tag)
+	}
+}
+
+// training читает общие поля тренировки.
+func (r *binaryReader) training() Training {
+	t := Training{
+		TrainingType:  r.string(),
+		Action:        int(r.int64()),
+		LenStep:       r.float64(),
+		Duration:      time.Duration(r.int64()),
+		Weight:        r.float64(),
+		StartedAt:     r.time(),
+		ElevationGain: r.float64(),
+	}
+
+	tagsCount := r.int64()
+	if r.err == nil && (tagsCount < 0 || tagsCount > int64(r.r.Len())) {
+		r.err = errors.New("неверное количество меток в двоичном представлении тренировки")
+		return t
+	}
+	for i := int64(0); i < tagsCount && r.err == nil; i++ {
+		t.Tags = append(t.Tags, r.string())
+	}
+
+	t.AvgHeartRate = int(r.int64())
+	t.BMR = r.float64()
+	t.FatigueFactor = r.float64()
+	t.Notes = r.string()
+
+	return t
+}
+
+// close возвращает первую ошибку чтения или ошибку, если после тренировки остались лишние байты.
+func (r *binaryReader) close() error {
+	if r.err == nil && r.r.Len() != 0 {
+		return errors.New("лишние байты в двоичном представлении тренировки")
+	}
+
+	return r.err
+}
+
+// MarshalBinary возвращает двоичное представление тренировки Бег.
+func (r Running) MarshalBinary() ([]byte, error) {
+	var w binaryWriter
+	w.buf.WriteByte(binaryTagRunning)
+	w.training(r.Training)
+	w.bool(r.LongRunBonus)
+
+	return w.buf.Bytes(), nil
+}
+
+// UnmarshalBinary восстанавливает тренировку Бег из двоичного представления.
+func (r *Running) UnmarshalBinary(data []byte) error {
+	reader := newBinaryReader(data)
+	reader.tag(binaryTagRunning)
+	training := reader.training()
+	longRunBonus := reader.bool()
+
+	if err := reader.close(); err != nil {
+		return err
+	}
+
+	*r = Running{Training: training, LongRunBonus: longRunBonus}
+	return nil
+}
+
+// MarshalBinary возвращает двоичное представление тренировки Ходьба.
+func (w Walking) MarshalBinary() ([]byte, error) {
+	var writer binaryWriter
+	writer.buf.WriteByte(binaryTagWalking)
+	writer.training(w.Training)
+	writer.float64(w.Height)
+
+	return writer.buf.Bytes(), nil
+}
+
+// UnmarshalBinary восстанавливает тренировку Ходьба из двоичного представления.
+func (w *Walking) UnmarshalBinary(data []byte) error {
+	reader := newBinaryReader(data)
+	reader.tag(binaryTagWalking)
+	training := reader.training()
+	height := reader.float64()
+
+	if err := reader.close(); err != nil {
+		return err
+	}
+
+	*w = Walking{Training: training, Height: height}
+	return nil
+}
+
+// MarshalBinary возвращает двоичное представление тренировки Скандинавская ходьба.
+// Это переопределенный метод MarshalBinary() из Walking.
+func (n NordicWalking) MarshalBinary() ([]byte, error) {
+	var writer binaryWriter
+	writer.buf.WriteByte(binaryTagNordicWalking)
+	writer.training(n.Training)
+	writer.float64(n.Height)
+
+	return writer.buf.Bytes(), nil
+}
+
+// UnmarshalBinary восстанавливает тренировку Скандинавская ходьба из двоичного представления.
+// Это переопределенный метод UnmarshalBinary() из Walking.
+func (n *NordicWalking) UnmarshalBinary(data []byte) error {
+	reader := newBinaryReader(data)
+	reader.tag(binaryTagNordicWalking)
+	training := reader.training()
+	height := reader.float64()
+
+	if err := reader.close(); err != nil {
+		return err
+	}
+
+	*n = NordicWalking{Walking: Walking{Training: training, Height: height}}
+	return nil
+}
+
+// MarshalBinary возвращает двоичное представление тренировки Плавание.
+func (s Swimming) MarshalBinary() ([]byte, error) {
+	var w binaryWriter
+	w.buf.WriteByte(binaryTagSwimming)
+	w.training(s.Training)
+	w.int64(int64(s.LengthPool))
+	w.int64(int64(s.CountPool))
+	w.int64(int64(len(s.PoolSets)))
+	for _, set := range s.PoolSets {
+		w.int64(int64(set.LengthPool))
+		w.int64(int64(set.CountPool))
+	}
+	w.int64(int64(s.RestBetweenSets))
+	w.bool(s.CaloriesIncludeRest)
+
+	return w.buf.Bytes(), nil
+}
+
+// UnmarshalBinary восстанавливает тренировку Плавание из двоичного представления.
+func (s *Swimming) UnmarshalBinary(data []byte) error {
+	reader := newBinaryReader(data)
+	reader.tag(binaryTagSwimming)
+	swimming := Swimming{
+		Training:   reader.training(),
+		LengthPool: int(reader.int64()),
+		CountPool:  int(reader.int64()),
+	}
+
+	setsCount := reader.int64()
+	if reader.err == nil && (setsCount < 0 || setsCount > int64(reader.r.Len())) {
+		return errors.New("неверное количество серий в двоичном представлении тренировки")
+	}
+	for i := int64(0); i < setsCount && reader.err == nil; i++ {
+		swimming.PoolSets = append(swimming.PoolSets, PoolSet{
+			LengthPool: int(reader.int64()),
+			CountPool:  int(reader.int64()),
+		})
+	}
+
+	swimming.RestBetweenSets = time.Duration(reader.int64())
+	swimming.CaloriesIncludeRest = reader.bool()
+
+	if err := reader.close(); err != nil {
+		return err
+	}
+
+	*s = swimming
+	return nil
+}
+
+// MarshalBinary возвращает двоичное представление тренировки на велотренажере.
+func (b StationaryBike) MarshalBinary() ([]byte, error) {
+	var w binaryWriter
+	w.buf.WriteByte(binaryTagStationaryBike)
+	w.training(b.Training)
+	w.int64(int64(b.ResistanceLevel))
+
+	return w.buf.Bytes(), nil
+}
+
+// UnmarshalBinary восстанавливает тренировку на велотренажере из двоичного представления.
+func (b *StationaryBike) UnmarshalBinary(data []byte) error {
+	reader := newBinaryReader(data)
+	reader.tag(binaryTagStationaryBike)
+	training := reader.training()
+	resistanceLevel := int(reader.int64())
+
+	if err := reader.close(); err != nil {
+		return err
+	}
+
+	*b = StationaryBike{Training: training, ResistanceLevel: resistanceLevel}
+	return nil
+}
+
+// MarshalBinary возвращает двоичное представление силовой тренировки.
+func (s Strength) MarshalBinary() ([]byte, error) {
+	var w binaryWriter
+	w.buf.WriteByte(binaryTagStrength)
+	w.training(s.Training)
+	w.int64(int64(s.Sets))
+	w.int64(int64(s.Reps))
+	w.float64(s.LoadKg)
+
+	return w.buf.Bytes(), nil
+}
+
+// UnmarshalBinary восстанавливает силовую тренировку из двоичного представления.
+func (s *Strength) UnmarshalBinary(data []byte) error {
+	reader := newBinaryReader(data)
+	reader.tag(binaryTagStrength)
+	strength := Strength{
+		Training: reader.training(),
+		Sets:     int(reader.int64()),
+		Reps:     int(reader.int64()),
+		LoadKg:   reader.float64(),
+	}
+
+	if err := reader.close(); err != nil {
+		return err
+	}
+
+	*s = strength
+	return nil
+}
+
+// UnmarshalTraining восстанавливает тренировку любого вида из двоичного представления,
+// определяя вид по первому байту-метке. При ошибке возвращает nil.
+func UnmarshalTraining(data []byte) (CaloriesCalculator, error) {
+	if len(data) == 0 {
+		return nil, errors.New("пустое двоичное представление тренировки")
+	}
+
+	switch data[0] {
+	case binaryTagRunning:
+		var r Running
+		if err := r.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		return r, nil
+	case binaryTagWalking:
+		var w Walking
+		if err := w.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		return w, nil
+	case binaryTagNordicWalking:
+		var n NordicWalking
+		if err := n.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case binaryTagSwimming:
+		var s Swimming
+		if err := s.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		return s, nil
+	case binaryTagStationaryBike:
+		var b StationaryBike
+		if err := b.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		return b, nil
+	case binaryTagStrength:
+		var s Strength
+		if err := s.UnmarshalBinary(data); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+
+	return nil, fmt.Errorf("%w: %d", ErrUnknownBinaryTag, data[0])
+}
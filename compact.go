@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// compactTypeCodes однобуквенные коды видов тренировок в компактном представлении.
+var compactTypeCodes = map[string]string{
+	RunningType:        "r",
+	WalkingType:        "w",
+	NordicWalkingType:  "n",
+	SwimmingType:       "s",
+	StationaryBikeType: "b",
+	StrengthType:       "g",
+}
+
+// compactCustomTypePrefix префикс вида тренировки, для которого нет однобуквенного кода.
+// После него идет название вида тренировки в base64 для URL.
+const compactCustomTypePrefix = "~"
+
+// compactSeparator разделитель полей компактного представления.
+const compactSeparator = "."
+
+// hundredths возвращает число в сотых, округленное так же, как при выводе с форматом %.2f.
+func hundredths(value float64) int64 {
+	displayed, _ := strconv.ParseFloat(strconv.FormatFloat(value, 'f', 2, 64), 64)
+
+	return int64(math.Round(displayed * 100))
+}
+
+// CompactEncode возвращает короткую строку, безопасную для URL и QR-кодов, с типом, дистанцией,
+// продолжительностью и калориями тренировки, например "r.dw.1e0.zsy".
+// Дистанция и калории сохраняются с точностью до сотых, продолжительность — до секунд,
+// числа записываются в системе счисления с основанием 36.
+// Восстановить информацию можно с помощью CompactDecode.
+func (i InfoMessage) CompactEncode() string {
+	typeCode, ok := compactTypeCodes[NormalizeType(i.TrainingType)]
+	if !ok {
+		typeCode = compactCustomTypePrefix + base64.RawURLEncoding.EncodeToString([]byte(i.TrainingType))
+	}
+
+	return strings.Join([]string{
+		typeCode,
+		strconv.FormatInt(hundredths(i.Distance), 36),
+		strconv.FormatInt(int64(math.Round(i.Duration.Seconds())), 36),
+		strconv.FormatInt(hundredths(i.Calories), 36),
+	}, compactSeparator)
+}
+
+// CompactDecode восстанавливает информацию о тренировке из строки, полученной с помощью CompactEncode.
+// Средняя скорость вычисляется по дистанции и продолжительности.
+func CompactDecode(s string) (InfoMessage, error) {
+	parts := strings.Split(s, compactSeparator)
+	if len(parts) != 4 {
+		return InfoMessage{}, fmt.Errorf("неверное компактное представление тренировки: %q", s)
+	}
+
+	trainingType, err := decodeCompactType(parts[0])
+	if err != nil {
+		return InfoMessage{}, err
+	}
+
+	var numbers [3]int64
+	for i, part := range parts[1:] {
+		numbers[i], err = strconv.ParseInt(part, 36, 64)
+		if err != nil {
+			return InfoMessage{}, fmt.Errorf("неверное число в компактном представлении тренировки: %w", err)
+		}
+	}
+
+	info := InfoMessage{
+		Training: Training{
+			TrainingType: trainingType,
+			Duration:     time.Duration(numbers[1]) * time.Second,
+		},
+		Distance: float64(numbers[0]) / 100,
+		Calories: float64(numbers[2]) / 100,
+	}
+
+	if hours := info.Duration.Hours(); hours != 0 {
+		info.Speed = info.Distance / hours
+	}
+
+	return info, nil
+}
+
+// decodeCompactType возвращает название вида тренировки по его коду в компактном представлении.
+func decodeCompactType(code string) (string, error) {
+	if strings.HasPrefix(code, compactCustomTypePrefix) {
+		name, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(code, compactCustomTypePrefix))
+		if err != nil {
+			return "", fmt.Errorf("неверный вид тренировки в компактном представлении: %w", err)
+		}
+		return string(name), nil
+	}
+
+	for trainingType, typeCode := range compactTypeCodes {
+		if typeCode == code {
+			return trainingType, nil
+		}
+	}
+
+	return "", errors.New("неизвестный код вида тренировки: " + strconv.Quote(code))
+}
@@ -0,0 +1,432 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportJSONL записывает в w информацию о тренировках в формате JSON Lines:
+// по одному объекту InfoMessage в строке.
+// Возвращает первую ошибку записи.
+func ExportJSONL(w io.Writer, trainings []CaloriesCalculator) error {
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+
+	for _, training := range trainings {
+		if err := encoder.Encode(training.TrainingInfo()); err != nil {
+			return err
+		}
+	}
+
+	return buffered.Flush()
+}
+
+// Названия столбцов CSV.
+const (
+	CSVColumnType            = "type"
+	CSVColumnDuration        = "duration_min"
+	CSVColumnDistance        = "distance_km"
+	CSVColumnSpeed           = "speed_kmh"
+	CSVColumnCalories        = "calories"
+	CSVColumnAction          = "action"
+	CSVColumnLenStep         = "len_step"
+	CSVColumnWeight          = "weight"
+	CSVColumnHeight          = "height"
+	CSVColumnLengthPool      = "length_pool"
+	CSVColumnCountPool       = "count_pool"
+	CSVColumnResistanceLevel = "resistance_level"
+	CSVColumnSets            = "sets"
+	CSVColumnReps            = "reps"
+	CSVColumnLoadKg          = "load_kg"
+	CSVColumnStartedAt       = "started_at"
+	CSVColumnElevationGain   = "elevation_gain"
+	CSVColumnTags            = "tags"
+	CSVColumnAvgHeartRate    = "avg_heart_rate"
+	CSVColumnBMR             = "bmr"
+	CSVColumnFatigueFactor   = "fatigue_factor"
+	CSVColumnLongRunBonus    = "long_run_bonus"
+	CSVColumnPoolSets        = "pool_sets"
+	CSVColumnRestBetweenSets = "rest_between_sets_min"
+	CSVColumnIncludeRest     = "calories_include_rest"
+	CSVColumnNotes           = "notes"
+)
+
+// Разделители значений внутри одного поля CSV.
+const (
+	CSVTagSeparator     = "|" // между метками в столбце tags, например "утро|соревнование"
+	CSVPoolSetSeparator = " " // между сериями в столбце pool_sets, например "50x20 25x40"
+	CSVPoolSetTimes     = "x" // между длиной бассейна и количеством пересечений в серии
+)
+
+// CSVColumns все столбцы CSV в порядке вывода по умолчанию.
+// Столбцы, которые не относятся к виду тренировки, например height для бега, остаются пустыми.
+// Время начала started_at записывается в формате RFC 3339, пустое значение — время не указано.
+var CSVColumns = []string{
+	CSVColumnType,
+	CSVColumnDuration,
+	CSVColumnDistance,
+	CSVColumnSpeed,
+	CSVColumnCalories,
+	CSVColumnAction,
+	CSVColumnLenStep,
+	CSVColumnWeight,
+	CSVColumnHeight,
+	CSVColumnLengthPool,
+	CSVColumnCountPool,
+	CSVColumnResistanceLevel,
+	CSVColumnSets,
+	CSVColumnReps,
+	CSVColumnLoadKg,
+	CSVColumnStartedAt,
+	CSVColumnElevationGain,
+	CSVColumnTags,
+	CSVColumnAvgHeartRate,
+	CSVColumnBMR,
+	CSVColumnFatigueFactor,
+	CSVColumnLongRunBonus,
+	CSVColumnPoolSets,
+	CSVColumnRestBetweenSets,
+	CSVColumnIncludeRest,
+	CSVColumnNotes,
+}
+
+// CSVOptions настройки экспорта в CSV. Нулевое значение означает настройки по умолчанию:
+// разделитель ',', строка заголовка и все столбцы из CSVColumns.
+type CSVOptions struct {
+	Delimiter  rune     // разделитель полей, например ',' или ';', 0 — ','
+	SkipHeader bool     // не выводить строку заголовка
+	Columns    []string // выводимые столбцы из CSVColumns, пустой список — все столбцы
+}
+
+// formatCSVFloat возвращает число с плавающей точкой без лишних нулей.
+func formatCSVFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// formatCSVPoolSets возвращает серии плавания в виде "50x20 25x40".
+func formatCSVPoolSets(poolSets []PoolSet) string {
+	sets := make([]string, len(poolSets))
+	for i, set := range poolSets {
+		sets[i] = strconv.Itoa(set.LengthPool) + CSVPoolSetTimes + strconv.Itoa(set.CountPool)
+	}
+
+	return strings.Join(sets, CSVPoolSetSeparator)
+}
+
+// csvRecord возвращает значения всех столбцов CSV для тренировки.
+// Возвращает ошибку, если метку нельзя записать, потому что она содержит CSVTagSeparator.
+func csvRecord(training CaloriesCalculator) (map[string]string, error) {
+	info := training.TrainingInfo()
+
+	for _, tag := range info.Tags {
+		if tag == "" || strings.Contains(tag, CSVTagSeparator) {
+			return nil, fmt.Errorf("метку %q нельзя записать в CSV: она пустая или содержит %q", tag, CSVTagSeparator)
+		}
+	}
+
+	record := map[string]string{
+		CSVColumnType:          info.TrainingType,
+		CSVColumnDuration:      formatCSVFloat(info.Duration.Minutes()),
+		CSVColumnDistance:      fmt.Sprintf("%.2f", info.Distance),
+		CSVColumnSpeed:         fmt.Sprintf("%.2f", info.Speed),
+		CSVColumnCalories:      fmt.Sprintf("%.2f", info.Calories),
+		CSVColumnAction:        strconv.Itoa(info.Action),
+		CSVColumnLenStep:       formatCSVFloat(info.LenStep),
+		CSVColumnWeight:        formatCSVFloat(info.Weight),
+		CSVColumnElevationGain: formatCSVFloat(info.ElevationGain),
+		CSVColumnTags:          strings.Join(info.Tags, CSVTagSeparator),
+		CSVColumnAvgHeartRate:  strconv.Itoa(info.AvgHeartRate),
+		CSVColumnBMR:           formatCSVFloat(info.BMR),
+		CSVColumnFatigueFactor: formatCSVFloat(info.FatigueFactor),
+		CSVColumnNotes:         strings.TrimSpace(info.Notes),
+	}
+
+	if !info.StartedAt.IsZero() {
+		record[CSVColumnStartedAt] = info.StartedAt.Format(time.RFC3339Nano)
+	}
+
+	switch t := training.(type) {
+	case Running:
+		record[CSVColumnLongRunBonus] = strconv.FormatBool(t.LongRunBonus)
+	case Walking:
+		record[CSVColumnHeight] = formatCSVFloat(t.Height)
+	case NordicWalking:
+		record[CSVColumnHeight] = formatCSVFloat(t.Height)
+	case Swimming:
+		record[CSVColumnLengthPool] = strconv.Itoa(t.LengthPool)
+		record[CSVColumnCountPool] = strconv.Itoa(t.CountPool)
+		record[CSVColumnPoolSets] = formatCSVPoolSets(t.PoolSets)
+		record[CSVColumnRestBetweenSets] = formatCSVFloat(t.RestBetweenSets.Minutes())
+		record[CSVColumnIncludeRest] = strconv.FormatBool(t.CaloriesIncludeRest)
+	case StationaryBike:
+		record[CSVColumnResistanceLevel] = strconv.Itoa(t.ResistanceLevel)
+	case Strength:
+		record[CSVColumnSets] = strconv.Itoa(t.Sets)
+		record[CSVColumnReps] = strconv.Itoa(t.Reps)
+		record[CSVColumnLoadKg] = formatCSVFloat(t.LoadKg)
+	}
+
+	return record, nil
+}
+
+// ExportCSV записывает в w информацию о тренировках в формате CSV с настройками opts.
+// Возвращает ошибку для неизвестного столбца, метки, которую нельзя записать, или первую ошибку записи.
+func ExportCSV(w io.Writer, trainings []CaloriesCalculator, opts CSVOptions) error {
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = CSVColumns
+	}
+
+	for _, column := range columns {
+		if !containsString(CSVColumns, column) {
+			return fmt.Errorf("неизвестный столбец CSV: %q", column)
+		}
+	}
+
+	writer := csv.NewWriter(w)
+	if opts.Delimiter != 0 {
+		writer.Comma = opts.Delimiter
+	}
+
+	if !opts.SkipHeader {
+		if err := writer.Write(columns); err != nil {
+			return err
+		}
+	}
+
+	for _, training := range trainings {
+		record, err := csvRecord(training)
+		if err != nil {
+			return err
+		}
+
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = record[column]
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// containsString сообщает, есть ли value в values.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AsMap возвращает плоскую карту с информацией о тренировке, например для text/template.
+// Ключи совпадают с названиями столбцов CSV:
+// type, notes (string), duration_min, distance_km, speed_kmh, calories, len_step, weight,
+// elevation_gain, bmr, fatigue_factor (float64), action, avg_heart_rate (int), started_at (time.Time),
+// tags ([]string), а также поля вида тренировки: long_run_bonus (bool) для бега, height (float64) для ходьбы,
+// length_pool, count_pool (int), pool_sets ([]PoolSet), rest_between_sets_min (float64)
+// и calories_include_rest (bool) для плавания, resistance_level (int) для велотренажера,
+// sets, reps (int) и load_kg (float64) для силовой тренировки.
+// Если у плавания заданы серии pool_sets, то length_pool и count_pool не учитываются в расчетах
+// и обычно равны 0: дистанция distance_km считается по всем сериям.
+// Срезы tags и pool_sets копируются, их изменение не затрагивает тренировку.
+func AsMap(c CaloriesCalculator) map[string]interface{} {
+	info := c.TrainingInfo()
+
+	fields := map[string]interface{}{
+		CSVColumnType:          info.TrainingType,
+		CSVColumnDuration:      info.Duration.Minutes(),
+		CSVColumnDistance:      info.Distance,
+		CSVColumnSpeed:         info.Speed,
+		CSVColumnCalories:      info.Calories,
+		CSVColumnAction:        info.Action,
+		CSVColumnLenStep:       info.LenStep,
+		CSVColumnWeight:        info.Weight,
+		CSVColumnStartedAt:     info.StartedAt,
+		CSVColumnElevationGain: info.ElevationGain,
+		CSVColumnTags:          append([]string(nil), info.Tags...),
+		CSVColumnAvgHeartRate:  info.AvgHeartRate,
+		CSVColumnBMR:           info.BMR,
+		CSVColumnFatigueFactor: info.FatigueFactor,
+		CSVColumnNotes:         strings.TrimSpace(info.Notes),
+	}
+
+	switch t := c.(type) {
+	case Running:
+		fields[CSVColumnLongRunBonus] = t.LongRunBonus
+	case Walking:
+		fields[CSVColumnHeight] = t.Height
+	case NordicWalking:
+		fields[CSVColumnHeight] = t.Height
+	case Swimming:
+		fields[CSVColumnLengthPool] = t.LengthPool
+		fields[CSVColumnCountPool] = t.CountPool
+		fields[CSVColumnPoolSets] = append([]PoolSet(nil), t.PoolSets...)
+		fields[CSVColumnRestBetweenSets] = t.RestBetweenSets.Minutes()
+		fields[CSVColumnIncludeRest] = t.CaloriesIncludeRest
+	case StationaryBike:
+		fields[CSVColumnResistanceLevel] = t.ResistanceLevel
+	case Strength:
+		fields[CSVColumnSets] = t.Sets
+		fields[CSVColumnReps] = t.Reps
+		fields[CSVColumnLoadKg] = t.LoadKg
+	}
+
+	return fields
+}
+
+// DistanceUnitKm единица измерения дистанции в JSON. Все дистанции в пакете считаются в км.
+const DistanceUnitKm = "km"
+
+// infoMessageJSON InfoMessage без собственных методов, чтобы избежать рекурсии в MarshalJSON.
+type infoMessageJSON InfoMessage
+
+// MarshalJSON возвращает JSON-представление InfoMessage с дополнительным полем "unit",
+// в котором указана единица измерения дистанции и скорости. Пробелы по краям заметок удаляются.
+func (i InfoMessage) MarshalJSON() ([]byte, error) {
+	i.Notes = strings.TrimSpace(i.Notes)
+
+	return json.Marshal(struct {
+		infoMessageJSON
+		Unit string `json:"unit"`
+	}{
+		infoMessageJSON: infoMessageJSON(i),
+		Unit:            DistanceUnitKm,
+	})
+}
+
+// htmlReportTemplate шаблон HTML-отчета о тренировках.
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Тренировки</title>
+</head>
+<body>
+<table>
+<thead>
+<tr><th>Тип тренировки</th><th>Длительность, мин</th><th>Дистанция, км</th><th>Ср. скорость, км/ч</th><th>Потрачено ккал</th><th>Заметки</th></tr>
+</thead>
+<tbody>
+{{- range .Rows}}
+<tr><td>{{.TrainingType}}</td><td>{{.Duration}}</td><td>{{.Distance}}</td><td>{{.Speed}}</td><td>{{.Calories}}</td><td>{{.Notes}}</td></tr>
+{{- end}}
+</tbody>
+<tfoot>
+<tr><th>Итого</th><th>{{.Total.Duration}}</th><th>{{.Total.Distance}}</th><th></th><th>{{.Total.Calories}}</th><th></th></tr>
+</tfoot>
+</table>
+</body>
+</html>
+`))
+
+// htmlReportRow строка HTML-отчета с числами, отформатированными как в InfoMessage.String().
+type htmlReportRow struct {
+	TrainingType string
+	Duration     string
+	Distance     string
+	Speed        string
+	Calories     string
+	Notes        string
+}
+
+// ExportHTML записывает в w HTML-страницу с таблицей тренировок и итоговой строкой.
+// Числа форматируются так же, как в InfoMessage.String(), типы тренировок и заметки экранируются.
+func ExportHTML(w io.Writer, trainings []CaloriesCalculator) error {
+	opts := DefaultFormatOptions
+
+	rows := make([]htmlReportRow, 0, len(trainings))
+	for _, training := range trainings {
+		info := training.TrainingInfo()
+		rows = append(rows, htmlReportRow{
+			TrainingType: info.TrainingType,
+			Duration:     opts.formatNumber("%v", info.Duration.Minutes()),
+			Distance:     opts.formatNumber("%.2f", info.Distance),
+			Speed:        opts.formatNumber("%.2f", info.Speed),
+			Calories:     opts.formatNumber("%.2f", info.Calories),
+			Notes:        strings.TrimSpace(info.Notes),
+		})
+	}
+
+	return htmlReportTemplate.Execute(w, struct {
+		Rows  []htmlReportRow
+		Total htmlReportRow
+	}{
+		Rows: rows,
+		Total: htmlReportRow{
+			Duration: opts.formatNumber("%v", TotalDuration(trainings).Minutes()),
+			Distance: opts.formatNumber("%.2f", TotalDistance(trainings)),
+			Calories: opts.formatNumber("%.2f", TotalCalories(trainings)),
+		},
+	})
+}
+
+// healthKitActivityTypes типы тренировок HKWorkoutActivityType для видов тренировок пакета.
+var healthKitActivityTypes = map[string]string{
+	RunningType:        "running",
+	WalkingType:        "walking",
+	NordicWalkingType:  "walking",
+	SwimmingType:       "swimming",
+	StationaryBikeType: "cycling",
+	StrengthType:       "traditionalStrengthTraining",
+}
+
+// HealthKitActivityOther тип тренировки HKWorkoutActivityType для неизвестного вида тренировки.
+const HealthKitActivityOther = "other"
+
+// healthKitWorkout тренировка в формате, совместимом с HKWorkout из Apple Health.
+type healthKitWorkout struct {
+	ActivityType      string  `json:"activityType"`
+	TotalEnergyBurned float64 `json:"totalEnergyBurned"` // ккал
+	TotalDistance     float64 `json:"totalDistance"`     // м
+	Duration          float64 `json:"duration"`          // с
+	StartDate         string  `json:"startDate,omitempty"`
+	EndDate           string  `json:"endDate,omitempty"`
+}
+
+// ExportHealthKit записывает в w JSON-массив тренировок с полями, совместимыми с HKWorkout
+// из Apple Health: activityType, totalEnergyBurned в ккал, totalDistance в м, duration в с,
+// а также startDate и endDate в формате RFC 3339, если известно время начала тренировки.
+// Вид тренировки приводится к каноническому виду с помощью NormalizeType,
+// неизвестным видам соответствует тип HealthKitActivityOther.
+func ExportHealthKit(w io.Writer, trainings []CaloriesCalculator) error {
+	workouts := make([]healthKitWorkout, 0, len(trainings))
+
+	for _, training := range trainings {
+		info := training.TrainingInfo()
+
+		activityType, ok := healthKitActivityTypes[NormalizeType(info.TrainingType)]
+		if !ok {
+			activityType = HealthKitActivityOther
+		}
+
+		workout := healthKitWorkout{
+			ActivityType:      activityType,
+			TotalEnergyBurned: info.Calories,
+			TotalDistance:     info.Distance * MInKm,
+			Duration:          info.Duration.Seconds(),
+		}
+
+		if !info.StartedAt.IsZero() {
+			workout.StartDate = info.StartedAt.Format(time.RFC3339)
+			workout.EndDate = info.StartedAt.Add(info.Duration).Format(time.RFC3339)
+		}
+
+		workouts = append(workouts, workout)
+	}
+
+	return json.NewEncoder(w).Encode(workouts)
+}
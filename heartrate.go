@@ -0,0 +1,99 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// Константы для расчета тренировочной нагрузки TRIMP по Банистеру.
+const (
+	TRIMPMultiplier = 0.64 // множитель резерва пульса
+	TRIMPExponent   = 1.92 // показатель экспоненты резерва пульса
+)
+
+// heartRateReserve возвращает долю резерва пульса, использованную на тренировке, от 0 до 1.
+// Формула расчета:
+// (средний_пульс - пульс_покоя) / (максимальный_пульс - пульс_покоя)
+func (t Training) heartRateReserve(restHR, maxHR int) float64 {
+	if t.AvgHeartRate == 0 || maxHR <= restHR {
+		return 0
+	}
+
+	reserve := float64(t.AvgHeartRate-restHR) / float64(maxHR-restHR)
+
+	return math.Max(0, math.Min(1, reserve))
+}
+
+// TRIMP возвращает тренировочную нагрузку TRIMP по Банистеру.
+// Формула расчета:
+// время_тренировки_в_минутах * резерв_пульса * 0.64 * e^(1.92 * резерв_пульса)
+// Если пульс не измерялся или пульс покоя не меньше максимального, возвращает 0.
+func (t Training) TRIMP(restHR, maxHR int) float64 {
+	reserve := t.heartRateReserve(restHR, maxHR)
+
+	return t.Duration.Minutes() * reserve * TRIMPMultiplier * math.Exp(TRIMPExponent*reserve)
+}
+
+// HRSample измерение пульса.
+type HRSample struct {
+	Time      time.Time // время измерения
+	HeartRate int       // пульс в уд/мин
+}
+
+// Константы для расчета пульсовых зон.
+const (
+	MaxHeartRateBase       = 220 // из этого числа вычитается возраст для оценки максимального пульса
+	HeartRateZonesCount    = 5   // количество пульсовых зон
+	HeartRateZoneLowerEdge = 0.5 // нижняя граница первой зоны как доля максимального пульса
+	HeartRateZoneWidth     = 0.1 // ширина зоны как доля максимального пульса
+)
+
+// MaxHeartRate возвращает оценку максимального пульса для возраста age.
+// Формула расчета:
+// 220 - возраст
+func MaxHeartRate(age int) int {
+	return MaxHeartRateBase - age
+}
+
+// HeartRateZone возвращает номер пульсовой зоны от 1 до 5 для пульса hr при максимальном пульсе maxHR.
+// Зона 1 — от 50% до 60% максимального пульса, зона 5 — от 90% до 100%.
+// Для пульса вне этих границ возвращает 0.
+func HeartRateZone(hr, maxHR int) int {
+	if hr <= 0 || maxHR <= 0 || hr > maxHR {
+		return 0
+	}
+
+	fraction := float64(hr) / float64(maxHR)
+	if fraction < HeartRateZoneLowerEdge {
+		return 0
+	}
+
+	zone := int((fraction-HeartRateZoneLowerEdge)/HeartRateZoneWidth) + 1
+
+	if zone > HeartRateZonesCount {
+		return HeartRateZonesCount
+	}
+
+	return zone
+}
+
+// TimeInZones возвращает время, проведенное в каждой из пяти пульсовых зон, для возраста age.
+// Каждое измерение длится до следующего, поэтому последнее измерение не учитывается.
+// Измерения должны быть упорядочены по времени. Измерения с пульсом вне зон не учитываются.
+func TimeInZones(samples []HRSample, age int) [HeartRateZonesCount]time.Duration {
+	var zones [HeartRateZonesCount]time.Duration
+	maxHR := MaxHeartRate(age)
+
+	for i := 0; i+1 < len(samples); i++ {
+		zone := HeartRateZone(samples[i].HeartRate, maxHR)
+		sampleDuration := samples[i+1].Time.Sub(samples[i].Time)
+
+		if zone == 0 || sampleDuration <= 0 {
+			continue
+		}
+
+		zones[zone-1] += sampleDuration
+	}
+
+	return zones
+}
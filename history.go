@@ -0,0 +1,227 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// DateLayout формат даты, по которому тренировки группируются по дням.
+const DateLayout = "2006-01-02"
+
+// Clock источник текущего времени для функций, которые зависят от него, например CurrentStreakWithClock.
+// В тестах можно передать часы, которые всегда возвращают одно и то же время.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc позволяет использовать функцию как Clock, например ClockFunc(time.Now).
+type ClockFunc func() time.Time
+
+// Now возвращает результат вызова f.
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock часы, которые возвращают системное время.
+var SystemClock Clock = ClockFunc(time.Now)
+
+// CurrentStreak возвращает количество дней подряд по системным часам,
+// в каждый из которых была хотя бы одна тренировка. См. CurrentStreakWithClock.
+func CurrentStreak(history []InfoMessage) int {
+	return CurrentStreakWithClock(history, SystemClock)
+}
+
+// CurrentStreakWithClock возвращает количество дней подряд, в каждый из которых была хотя бы одна тренировка.
+// Текущий день определяется по часам clock.
+// Серия заканчивается сегодня или вчера: пока текущий день не закончился, серия не считается прерванной.
+// Несколько тренировок в один день считаются одним днем, тренировки без StartedAt не учитываются.
+// Дни определяются по местному времени.
+func CurrentStreakWithClock(history []InfoMessage, clock Clock) int {
+	trainingDays := make(map[string]bool)
+	for _, info := range history {
+		if info.StartedAt.IsZero() {
+			continue
+		}
+		trainingDays[info.StartedAt.Local().Format(DateLayout)] = true
+	}
+
+	now := clock.Now().Local()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	if !trainingDays[day.Format(DateLayout)] {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	streak := 0
+	for trainingDays[day.Format(DateLayout)] {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+
+	return streak
+}
+
+// Average возвращает среднее значение метрики metric по тренировкам из history,
+// например Average(history, func(i InfoMessage) float64 { return i.Calories }).
+// Для пустой истории возвращает 0.
+func Average(history []InfoMessage, metric func(InfoMessage) float64) float64 {
+	if len(history) == 0 {
+		return 0
+	}
+
+	total := 0.0
+	for _, info := range history {
+		total += metric(info)
+	}
+
+	return total / float64(len(history))
+}
+
+// BestPace возвращает лучший (минимальный) средний темп в history и тренировку, на которой он достигнут.
+// Тренировки без дистанции не учитываются.
+// Если подходящих тренировок нет, возвращает нулевой темп и пустую InfoMessage.
+func BestPace(history []InfoMessage) (time.Duration, InfoMessage) {
+	var bestPace time.Duration
+	var best InfoMessage
+
+	for _, info := range history {
+		pace := info.Pace()
+		if pace == 0 {
+			continue
+		}
+
+		if bestPace == 0 || pace < bestPace {
+			bestPace = pace
+			best = info
+		}
+	}
+
+	return bestPace, best
+}
+
+// EddingtonNumber возвращает число Эддингтона E — наибольшее число, для которого есть
+// хотя бы E дней с дистанцией не меньше E км. dailyDistances — дистанции по дням в км.
+// Для пустого списка возвращает 0.
+func EddingtonNumber(dailyDistances []float64) int {
+	distances := make([]float64, len(dailyDistances))
+	copy(distances, dailyDistances)
+	sort.Sort(sort.Reverse(sort.Float64Slice(distances)))
+
+	eddington := 0
+	for i, distance := range distances {
+		if distance < float64(i+1) {
+			break
+		}
+		eddington = i + 1
+	}
+
+	return eddington
+}
+
+// Константы для оценки фитнес-возраста.
+const (
+	FitnessNormMinutesPerWeek = 150 // рекомендованная ВОЗ продолжительность умеренной активности в неделю
+	FitnessYearsPerNorm       = 5   // на сколько лет меняется возраст при отклонении нагрузки на одну норму
+	FitnessMaxAgeShift        = 10  // максимальное отличие фитнес-возраста от календарного
+	FitnessIntensityShift     = 0.5 // вес минуты тренировки при нулевой интенсивности
+	DaysInWeek                = 7   // количество дней в неделе
+)
+
+// FitnessAge возвращает оценку фитнес-возраста по недавним тренировкам.
+// Нагрузка за неделю считается как сумма время_тренировки_в_минутах * (0.5 + интенсивность)
+// по всем тренировкам, деленная на количество недель между первой и последней тренировкой (не меньше одной),
+// где интенсивность — отношение средней скорости к пороговой скорости для типа тренировки.
+// Формула расчета:
+// возраст - 5 * (нагрузка_за_неделю / 150 - 1)
+// Фитнес-возраст отличается от календарного не больше чем на 10 лет.
+// Для пустой истории возвращает календарный возраст.
+func FitnessAge(history []InfoMessage, chronologicalAge int) int {
+	if len(history) == 0 {
+		return chronologicalAge
+	}
+
+	effectiveMinutes := 0.0
+	var first, last time.Time
+	for _, info := range history {
+		effectiveMinutes += info.Duration.Minutes() * (FitnessIntensityShift + info.intensity())
+
+		if info.StartedAt.IsZero() {
+			continue
+		}
+		if first.IsZero() || info.StartedAt.Before(first) {
+			first = info.StartedAt
+		}
+		if last.IsZero() || info.StartedAt.After(last) {
+			last = info.StartedAt
+		}
+	}
+
+	weeks := math.Max(1, last.Sub(first).Hours()/HoursInDay/DaysInWeek)
+	normRatio := effectiveMinutes / weeks / FitnessNormMinutesPerWeek
+
+	shift := FitnessYearsPerNorm * (normRatio - 1)
+	shift = math.Max(-FitnessMaxAgeShift, math.Min(FitnessMaxAgeShift, shift))
+
+	return chronologicalAge - int(math.Round(shift))
+}
+
+// AverageDuration возвращает среднюю продолжительность тренировок из history.
+// Продолжительности суммируются в float64, поэтому очень большая суммарная продолжительность
+// не приводит к переполнению time.Duration. Для пустой истории возвращает 0.
+func AverageDuration(history []InfoMessage) time.Duration {
+	return time.Duration(Average(history, func(info InfoMessage) float64 {
+		return float64(info.Duration)
+	}))
+}
+
+// RecencyWeightedAverage возвращает среднее значение метрики metric по тренировкам из history,
+// в котором более свежие тренировки весят больше: вес тренировки уменьшается вдвое
+// каждые halfLife относительно самой поздней тренировки.
+// Формула расчета:
+// сумма(метрика * вес) / сумма(вес), где вес = 0.5 ^ ((самое_позднее_начало - начало_тренировки) / halfLife)
+// Если хотя бы у одной тренировки нет StartedAt или halfLife <= 0, возвращает обычное среднее Average.
+// Для пустой истории возвращает 0.
+func RecencyWeightedAverage(history []InfoMessage, metric func(InfoMessage) float64, halfLife time.Duration) float64 {
+	if halfLife <= 0 {
+		return Average(history, metric)
+	}
+
+	var latest time.Time
+	for _, info := range history {
+		if info.StartedAt.IsZero() {
+			return Average(history, metric)
+		}
+		if info.StartedAt.After(latest) {
+			latest = info.StartedAt
+		}
+	}
+
+	total, totalWeight := 0.0, 0.0
+	for _, info := range history {
+		weight := math.Pow(0.5, float64(latest.Sub(info.StartedAt))/float64(halfLife))
+		total += metric(info) * weight
+		totalWeight += weight
+	}
+
+	if totalWeight == 0 {
+		return 0
+	}
+
+	return total / totalWeight
+}
+
+// AddToRollingLoad возвращает скользящую нагрузку после очередного дня тренировок.
+// Нагрузка current сначала уменьшается на долю decayPerDay за прошедший день, затем к ней прибавляется
+// вклад тренировки session — количество потраченных килокалорий.
+// Формула расчета:
+// нагрузка * (1 - decayPerDay) + потрачено_ккал
+// Функцию нужно вызывать один раз на каждый день, в день отдыха — с пустой InfoMessage.
+// Например, decayPerDay = 1/7 дает острую (недельную) нагрузку, а 1/28 — хроническую;
+// их отношение — acute:chronic workload ratio. decayPerDay ограничивается промежутком от 0 до 1,
+// отрицательная или нулевая нагрузка current считается нулевой, и результат равен вкладу тренировки.
+func AddToRollingLoad(current float64, session InfoMessage, decayPerDay float64) float64 {
+	decayPerDay = math.Min(1, math.Max(0, decayPerDay))
+
+	return math.Max(0, current)*(1-decayPerDay) + session.Calories
+}
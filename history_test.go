@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCurrentStreakWithClock(t *testing.T) {
+	now := time.Date(2024, time.March, 10, 18, 30, 0, 0, time.Local)
+	clock := ClockFunc(func() time.Time { return now })
+
+	// session возвращает тренировку, начатую daysAgo дней назад в hour часов.
+	session := func(daysAgo, hour int) InfoMessage {
+		return InfoMessage{Training: Training{
+			StartedAt: time.Date(2024, time.March, 10-daysAgo, hour, 0, 0, 0, time.Local),
+		}}
+	}
+
+	tests := []struct {
+		name    string
+		history []InfoMessage
+		want    int
+	}{
+		{name: "empty history", history: nil, want: 0},
+		{name: "today only", history: []InfoMessage{session(0, 7)}, want: 1},
+		{name: "today and previous days", history: []InfoMessage{session(0, 7), session(1, 7), session(2, 7)}, want: 3},
+		{name: "yesterday only", history: []InfoMessage{session(1, 20)}, want: 1},
+		{name: "streak ending yesterday", history: []InfoMessage{session(1, 20), session(2, 8)}, want: 2},
+		{name: "gap before yesterday", history: []InfoMessage{session(0, 7), session(1, 7), session(3, 7)}, want: 2},
+		{name: "last training two days ago", history: []InfoMessage{session(2, 7), session(3, 7)}, want: 0},
+		{name: "several sessions a day", history: []InfoMessage{session(0, 7), session(0, 18), session(1, 6), session(1, 21)}, want: 2},
+		{name: "sessions without start time", history: []InfoMessage{{}, {}}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CurrentStreakWithClock(tt.history, clock); got != tt.want {
+				t.Errorf("CurrentStreakWithClock() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
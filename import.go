@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvDelimiters разделители полей, которые ImportCSV распознает по строке заголовка.
+var csvDelimiters = []rune{',', ';', '\t'}
+
+// csvRow значения строки CSV по названиям столбцов с разбором чисел.
+// Запоминает первую ошибку разбора.
+type csvRow struct {
+	values map[string]string
+	err    error
+}
+
+// value возвращает значение столбца column без пробелов по краям.
+// Пустое значение, отсутствующий столбец или уже случившаяся ошибка дают пустую строку.
+func (r *csvRow) value(column string) string {
+	if r.err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(r.values[column])
+}
+
+// fail запоминает ошибку разбора столбца column, если ошибок еще не было.
+func (r *csvRow) fail(column string, err error) {
+	if r.err == nil {
+		r.err = fmt.Errorf("столбец %s: %w", column, err)
+	}
+}
+
+// float возвращает число с плавающей точкой из столбца column. Пустое значение — 0.
+func (r *csvRow) float(column string) float64 {
+	value := r.value(column)
+	if value == "" {
+		return 0
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		r.fail(column, err)
+	}
+
+	return number
+}
+
+// int возвращает целое число из столбца column. Пустое значение — 0.
+func (r *csvRow) int(column string) int {
+	value := r.value(column)
+	if value == "" {
+		return 0
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		r.fail(column, err)
+	}
+
+	return number
+}
+
+// bool возвращает логическое значение из столбца column. Пустое значение — false.
+func (r *csvRow) bool(column string) bool {
+	value := r.value(column)
+	if value == "" {
+		return false
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		r.fail(column, err)
+	}
+
+	return b
+}
+
+// minutes возвращает продолжительность из столбца column, записанную в минутах. Пустое значение — 0.
+func (r *csvRow) minutes(column string) time.Duration {
+	return time.Duration(math.Round(r.float(column) * float64(time.Minute)))
+}
+
+// time возвращает время в формате RFC 3339 из столбца column. Пустое значение — нулевое время.
+func (r *csvRow) time(column string) time.Time {
+	value := r.value(column)
+	if value == "" {
+		return time.Time{}
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		r.fail(column, err)
+	}
+
+	return t
+}
+
+// tags возвращает метки из столбца column, разделенные CSVTagSeparator. Пустое значение — nil.
+func (r *csvRow) tags(column string) []string {
+	value := r.value(column)
+	if value == "" {
+		return nil
+	}
+
+	return strings.Split(value, CSVTagSeparator)
+}
+
+// poolSets возвращает серии плавания из столбца column в виде "50x20 25x40". Пустое значение — nil.
+func (r *csvRow) poolSets(column string) []PoolSet {
+	value := r.value(column)
+	if value == "" {
+		return nil
+	}
+
+	var poolSets []PoolSet
+	for _, set := range strings.Fields(value) {
+		lengthPool, countPool, ok := strings.Cut(set, CSVPoolSetTimes)
+		length, lengthErr := strconv.Atoi(lengthPool)
+		count, countErr := strconv.Atoi(countPool)
+		if !ok || lengthErr != nil || countErr != nil {
+			r.fail(column, fmt.Errorf("неверная серия %q", set))
+			return nil
+		}
+		poolSets = append(poolSets, PoolSet{LengthPool: length, CountPool: count})
+	}
+
+	return poolSets
+}
+
+// training возвращает тренировку вида, указанного в столбце type.
+func (r *csvRow) training() (CaloriesCalculator, error) {
+	trainingType := NormalizeType(r.values[CSVColumnType])
+
+	training := Training{
+		TrainingType:  trainingType,
+		Action:        r.int(CSVColumnAction),
+		LenStep:       r.float(CSVColumnLenStep),
+		Duration:      r.minutes(CSVColumnDuration),
+		Weight:        r.float(CSVColumnWeight),
+		StartedAt:     r.time(CSVColumnStartedAt),
+		ElevationGain: r.float(CSVColumnElevationGain),
+		Tags:          r.tags(CSVColumnTags),
+		AvgHeartRate:  r.int(CSVColumnAvgHeartRate),
+		BMR:           r.float(CSVColumnBMR),
+		FatigueFactor: r.float(CSVColumnFatigueFactor),
+		Notes:         r.values[CSVColumnNotes],
+	}
+
+	var result CaloriesCalculator
+	switch trainingType {
+	case RunningType:
+		result = Running{Training: training, LongRunBonus: r.bool(CSVColumnLongRunBonus)}
+	case WalkingType:
+		result = Walking{Training: training, Height: r.float(CSVColumnHeight)}
+	case NordicWalkingType:
+		result = NordicWalking{Walking: Walking{Training: training, Height: r.float(CSVColumnHeight)}}
+	case SwimmingType:
+		result = Swimming{
+			Training:            training,
+			LengthPool:          r.int(CSVColumnLengthPool),
+			CountPool:           r.int(CSVColumnCountPool),
+			PoolSets:            r.poolSets(CSVColumnPoolSets),
+			RestBetweenSets:     r.minutes(CSVColumnRestBetweenSets),
+			CaloriesIncludeRest: r.bool(CSVColumnIncludeRest),
+		}
+	case StationaryBikeType:
+		result = StationaryBike{Training: training, ResistanceLevel: r.int(CSVColumnResistanceLevel)}
+	case StrengthType:
+		result = Strength{
+			Training: training,
+			Sets:     r.int(CSVColumnSets),
+			Reps:     r.int(CSVColumnReps),
+			LoadKg:   r.float(CSVColumnLoadKg),
+		}
+	default:
+		return nil, fmt.Errorf("неизвестный вид тренировки: %q", r.values[CSVColumnType])
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return result, nil
+}
+
+// detectCSVDelimiter возвращает разделитель из csvDelimiters, при котором строка заголовка header
+// состоит только из известных столбцов CSVColumns.
+func detectCSVDelimiter(header string) (rune, error) {
+	header = strings.TrimRight(header, "\r\n")
+
+	for _, delimiter := range csvDelimiters {
+		known := true
+		for _, column := range strings.Split(header, string(delimiter)) {
+			if !containsString(CSVColumns, column) {
+				known = false
+				break
+			}
+		}
+		if known {
+			return delimiter, nil
+		}
+	}
+
+	return 0, fmt.Errorf("неизвестные столбцы в заголовке CSV: %q", header)
+}
+
+// ImportCSV читает тренировки из CSV в формате ExportCSV со строкой заголовка.
+// Разделитель полей — ',', ';' или табуляция — определяется по строке заголовка.
+// Столбцы могут идти в любом порядке, обязателен только столбец type, по которому
+// определяется вид тренировки с помощью NormalizeType. Пустые значения считаются нулевыми.
+// Вычисляемые столбцы distance_km, speed_kmh и calories не читаются: показатели пересчитываются.
+// Ошибки в строках данных содержат номер строки файла.
+func ImportCSV(r io.Reader) ([]CaloriesCalculator, error) {
+	buffered := bufio.NewReader(r)
+
+	headerLine, err := buffered.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if strings.TrimSpace(headerLine) == "" {
+		return nil, errors.New("в CSV нет строки заголовка")
+	}
+
+	delimiter, err := detectCSVDelimiter(headerLine)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(io.MultiReader(strings.NewReader(headerLine), buffered))
+	reader.Comma = delimiter
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	if !containsString(header, CSVColumnType) {
+		return nil, fmt.Errorf("в CSV нет столбца %s", CSVColumnType)
+	}
+
+	var trainings []CaloriesCalculator
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		row := csvRow{values: make(map[string]string, len(header))}
+		for i, column := range header {
+			row.values[column] = record[i]
+		}
+
+		training, err := row.training()
+		if err != nil {
+			return nil, fmt.Errorf("строка %d: %w", line, err)
+		}
+
+		trainings = append(trainings, training)
+	}
+
+	return trainings, nil
+}
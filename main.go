@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -14,20 +16,93 @@ const (
 	CmInM      = 100  // количество сантиметров в одном метре
 )
 
-// Training общая структура для всех тренировок
+// Названия видов тренировок.
+const (
+	RunningType        = "Бег"
+	WalkingType        = "Ходьба"
+	NordicWalkingType  = "Скандинавская ходьба"
+	SwimmingType       = "Плавание"
+	StationaryBikeType = "Велотренажер"
+	StrengthType       = "Силовая тренировка"
+)
+
+// trainingTypeAliases сопоставляет распространенные названия видов тренировок в нижнем регистре каноническим.
+var trainingTypeAliases = map[string]string{
+	"бег":     RunningType,
+	"running": RunningType,
+	"run":     RunningType,
+	"ходьба":  WalkingType,
+	"walking": WalkingType,
+	"walk":    WalkingType,
+
+	"скандинавская ходьба": NordicWalkingType,
+	"nordic walking": NordicWalkingType,
+
+	"плавание": SwimmingType,
+	"swimming": SwimmingType,
+	"swim":     SwimmingType,
+
+	"велотренажер":    StationaryBikeType,
+	"stationary bike": StationaryBikeType,
+
+	"силовая тренировка": StrengthType,
+	"силовая":            StrengthType,
+	"strength":           StrengthType,
+}
+
+// NormalizeType возвращает каноническое название вида тренировки для s без учета регистра
+// и пробелов по краям, например "running" и " БЕГ" превращаются в "Бег".
+// Неизвестные названия возвращаются без изменений.
+func NormalizeType(s string) string {
+	if canonical, ok := trainingTypeAliases[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return canonical
+	}
+
+	return s
+}
+
+// Training общая структура для всех тренировок.
+// Из-за среза Tags Training, все виды тренировок и InfoMessage несравнимы: их нельзя сравнивать
+// с помощью == и использовать как ключи карт. Для сравнения тренировок используйте метод SameSession.
 type Training struct {
-	TrainingType string        // тип тренировки
-	Action       int           // количество повторов(шаги, гребки при плавании)
-	LenStep      float64       // длина одного шага или гребка в м
-	Duration     time.Duration // продолжительность тренировки
-	Weight       float64       // вес пользователя в кг
+	TrainingType  string        // тип тренировки
+	Action        int           // количество повторов(шаги, гребки при плавании)
+	LenStep       float64       // длина одного шага или гребка в м
+	Duration      time.Duration // продолжительность тренировки
+	Weight        float64       // вес пользователя в кг
+	StartedAt     time.Time     // время начала тренировки, нулевое значение — время не указано
+	ElevationGain float64       // набор высоты в м, отрицательное значение — спуск
+	Tags          []string      // произвольные метки тренировки, например "утро" или "соревнование"
+	AvgHeartRate  int           // средний пульс за тренировку в уд/мин, 0 — пульс не измерялся
+	BMR           float64       // базовый обмен веществ пользователя в ккал в сутки, 0 — не указан
+	FatigueFactor float64       // доля длины шага, теряемая за каждый час тренировки из-за усталости
+	Notes         string        // заметки пользователя о тренировке, не влияют на расчеты
+}
+
+// IsZero сообщает, что тренировка не заполнена, то есть все ее поля имеют нулевые значения.
+func (t Training) IsZero() bool {
+	return t.TrainingType == "" && t.Action == 0 && t.LenStep == 0 && t.Duration == 0 && t.Weight == 0 &&
+		t.StartedAt.IsZero() && t.ElevationGain == 0 && len(t.Tags) == 0 && t.AvgHeartRate == 0 && t.BMR == 0 && t.FatigueFactor == 0 && t.Notes == ""
+}
+
+// effectiveLenStep возвращает среднюю длину шага с учетом усталости.
+// Длина шага линейно уменьшается от LenStep в начале тренировки до LenStep * (1 - FatigueFactor * время_в_часах)
+// в конце, поэтому средняя длина шага равна:
+// длина_шага * (1 - коэффициент_усталости * время_тренировки_в_часах / 2)
+// Средняя длина шага не бывает отрицательной. При FatigueFactor == 0 возвращает LenStep.
+func (t Training) effectiveLenStep() float64 {
+	if t.FatigueFactor == 0 {
+		return t.LenStep
+	}
+
+	return t.LenStep * math.Max(0, 1-t.FatigueFactor*t.Duration.Hours()/2)
 }
 
 // distance возвращает дистанцию, которую преодолел пользователь.
 // Формула расчета:
-// количество_повторов * длина_шага / м_в_км
+// количество_повторов * средняя_длина_шага / м_в_км
 func (t Training) distance() float64 {
-	distance := float64(t.Action) * t.LenStep / MInKm
+	distance := float64(t.Action) * t.effectiveLenStep() / MInKm
 	return distance
 }
 
@@ -43,9 +118,17 @@ func (t Training) meanSpeed() float64 {
 	return meanSpeed
 }
 
+// MovingTimeRatio возвращает долю времени в движении от продолжительности тренировки.
+// Для тренировок без пауз возвращает 1.
+func (t Training) MovingTimeRatio() float64 {
+	return 1
+}
+
 // Calories возвращает количество потраченных килокалорий на тренировке.
 // Пока возвращаем 0, так как этот метод будет переопределяться для каждого типа тренировки.
+// В режиме StrictMode вызов для тренировки известного вида вызывает панику.
 func (t Training) Calories() float64 {
+	t.checkSemantics("Calories")
 	return 0
 }
 
@@ -58,7 +141,9 @@ type InfoMessage struct {
 }
 
 // TrainingInfo возвращает труктуру InfoMessage, в которой хранится вся информация о проведенной тренировке.
+// В режиме StrictMode вызов для тренировки известного вида вызывает панику.
 func (t Training) TrainingInfo() InfoMessage {
+	t.checkSemantics("TrainingInfo")
 
 	return InfoMessage{
 		Training: t,
@@ -68,19 +153,113 @@ func (t Training) TrainingInfo() InfoMessage {
 	}
 }
 
+// FormatOptions настройки вывода информации о тренировке.
+type FormatOptions struct {
+	DecimalSeparator rune         // разделитель целой и дробной части чисел
+	Language         Language     // язык текстовых описаний тренировки
+	RoundingMode     RoundingMode // способ округления чисел с фиксированным количеством знаков после запятой
+}
+
+// RoundingMode способ округления чисел при выводе.
+type RoundingMode int
+
+// Способы округления чисел при выводе.
+const (
+	RoundingDefault  RoundingMode = iota // как в fmt: по точному двоичному значению числа, например 2.675 -> 2.67
+	RoundingHalfUp                       // половина округляется от нуля по десятичной записи, 2.675 -> 2.68
+	RoundingHalfEven                     // банковское округление половины к четной цифре, 2.665 -> 2.66, 2.675 -> 2.68
+)
+
+// Round возвращает value, округленное до places знаков после запятой способом из настроек.
+// Округление выполняется по кратчайшей десятичной записи числа, поэтому 2.675 считается ровно половиной.
+// Для RoundingDefault возвращает value без изменений: округлит fmt при выводе.
+func (o FormatOptions) Round(value float64, places int) float64 {
+	if o.RoundingMode == RoundingDefault || math.IsInf(value, 0) || math.IsNaN(value) {
+		return value
+	}
+
+	shifted, err := strconv.ParseFloat(strconv.FormatFloat(value, 'f', -1, 64)+"e"+strconv.Itoa(places), 64)
+	if err != nil {
+		return value
+	}
+
+	if o.RoundingMode == RoundingHalfEven {
+		shifted = math.RoundToEven(shifted)
+	} else {
+		shifted = math.Round(shifted)
+	}
+
+	return shifted / math.Pow10(places)
+}
+
+// DefaultFormatOptions настройки вывода по умолчанию.
+var DefaultFormatOptions = FormatOptions{
+	DecimalSeparator: '.',
+	Language:         LanguageRU,
+}
+
+// formatNumber возвращает число, отформатированное по format, с разделителем дробной части из настроек.
+// Для форматов вида %.2f число предварительно округляется способом из настроек.
+func (o FormatOptions) formatNumber(format string, value float64) string {
+	if strings.HasPrefix(format, "%.") && strings.HasSuffix(format, "f") {
+		if places, err := strconv.Atoi(format[2 : len(format)-1]); err == nil {
+			value = o.Round(value, places)
+		}
+	}
+
+	return o.replaceSeparator(fmt.Sprintf(format, value))
+}
+
+// replaceSeparator заменяет точку в отформатированном числе на разделитель дробной части из настроек.
+func (o FormatOptions) replaceSeparator(formatted string) string {
+	if o.DecimalSeparator == 0 || o.DecimalSeparator == '.' {
+		return formatted
+	}
+
+	return strings.Replace(formatted, ".", string(o.DecimalSeparator), 1)
+}
+
 // String возвращает строку с информацией о проведенной тренировке.
 func (i InfoMessage) String() string {
+	return i.StringWith(DefaultFormatOptions)
+}
+
+// hasDistance сообщает, есть ли у вида тренировки дистанция и скорость.
+// У тренировок на велотренажере и силовых тренировок их нет.
+func (i InfoMessage) hasDistance() bool {
+	switch NormalizeType(i.TrainingType) {
+	case StationaryBikeType, StrengthType:
+		return false
+	}
 
-	return fmt.Sprintf("Тип тренировки: %s\nДлительность: %v мин\nДистанция: %.2f км.\nСр. скорость: %.2f км/ч\nПотрачено ккал: %.2f\n",
+	return true
+}
+
+// StringWith возвращает строку с информацией о проведенной тренировке, отформатированную по настройкам opts.
+// Для видов тренировок без дистанции и скорости, то есть велотренажера и силовой тренировки, эти строки не выводятся.
+func (i InfoMessage) StringWith(opts FormatOptions) string {
+	if !i.hasDistance() {
+		return fmt.Sprintf("Тип тренировки: %s\nДлительность: %s мин\nПотрачено ккал: %s\n",
+			i.TrainingType,
+			opts.formatNumber("%v", i.Duration.Minutes()),
+			opts.formatNumber("%.2f", i.Calories),
+		)
+	}
+
+	return fmt.Sprintf("Тип тренировки: %s\nДлительность: %s мин\nДистанция: %s км.\nСр. скорость: %s км/ч\nПотрачено ккал: %s\n",
 		i.TrainingType,
-		i.Duration.Minutes(),
-		i.Distance,
-		i.Speed,
-		i.Calories,
+		opts.formatNumber("%v", i.Duration.Minutes()),
+		opts.formatNumber("%.2f", i.Distance),
+		opts.formatNumber("%.2f", i.Speed),
+		opts.formatNumber("%.2f", i.Calories),
 	)
 }
 
-// CaloriesCalculator интерфейс для структур: Running, Walking и Swimming.
+// CaloriesCalculator интерфейс для структур: Running, Walking, NordicWalking, Swimming, StationaryBike и Strength.
+// Расход калорий при беге, ходьбе и плавании пропорционален весу пользователя, при нулевом весе он равен 0.
+// На велотренажере расход зависит только от мощности и от веса не зависит, а при силовой тренировке
+// при нулевом весе остается расход на работу по подъему веса снаряда.
+// Для проверки заполнения тренировки используйте SafeCalories и SafeReadData.
 type CaloriesCalculator interface {
 	Calories() float64
 	TrainingInfo() InfoMessage
@@ -90,16 +269,27 @@ type CaloriesCalculator interface {
 const (
 	CaloriesMeanSpeedMultiplier = 18   // множитель средней скорости бега
 	CaloriesMeanSpeedShift      = 1.79 // коэффициент изменения средней скорости
+
+	LongRunThreshold = 90 * time.Minute // продолжительность, после которой начисляется надбавка за длительный бег
+	LongRunBonusRate = 0.1              // надбавка к калориям за время бега сверх LongRunThreshold
 )
 
 // Running структура, описывающая тренировку Бег.
 type Running struct {
 	Training
+	LongRunBonus bool // начислять ли надбавку к калориям за длительный бег
+}
+
+// IsZero сообщает, что тренировка Бег не заполнена.
+// Это переопределенный метод IsZero() из Training.
+func (r Running) IsZero() bool {
+	return r.Training.IsZero() && !r.LongRunBonus
 }
 
 // Calories возввращает количество потраченных килокалория при беге.
 // Формула расчета:
 // ((18 * средняя_скорость_в_км/ч * 1.79) * вес_спортсмена_в_кг / м_в_км * время_тренировки_в_часах * мин_в_часе) -
+// плюс надбавка за длительный бег, если она включена с помощью LongRunBonus.
 // Это переопределенный метод Calories() из Training.
 func (r Running) Calories() float64 {
 	runnigMeanSpeed := r.meanSpeed()
@@ -109,8 +299,97 @@ func (r Running) Calories() float64 {
 
 	spentCaloriesWhileRunning := runningMeanSpeedModifier * r.Weight / MInKm * runningTimeInMinutes
 
-	return spentCaloriesWhileRunning
+	return spentCaloriesWhileRunning + r.longRunBonus(spentCaloriesWhileRunning)
+
+}
+
+// longRunBonus возвращает надбавку к калориям за длительный бег, если она включена с помощью LongRunBonus.
+// При длительном беге обмен веществ меняется, поэтому калории, приходящиеся на время
+// сверх 90 минут, увеличиваются на 10%.
+// Формула расчета:
+// калории * (время_тренировки - 90_минут) / время_тренировки * 0.1
+func (r Running) longRunBonus(calories float64) float64 {
+	if !r.LongRunBonus || r.Duration <= LongRunThreshold {
+		return 0
+	}
+
+	longRunShare := float64(r.Duration-LongRunThreshold) / float64(r.Duration)
+
+	return calories * longRunShare * LongRunBonusRate
+}
+
+// CaloriesBreakdown возвращает промежуточные значения формулы расчета калорий при беге.
+// Ключ "calories" содержит итоговое значение, совпадающее с Calories().
+func (r Running) CaloriesBreakdown() map[string]float64 {
+	runnigMeanSpeed := r.meanSpeed()
+	runningTimeInMinutes := r.Duration.Hours() * MinInHours
+	runningMeanSpeedModifier := CaloriesMeanSpeedMultiplier*runnigMeanSpeed + CaloriesMeanSpeedShift
+	baseCalories := runningMeanSpeedModifier * r.Weight / MInKm * runningTimeInMinutes
 
+	return map[string]float64{
+		"runningMeanSpeed":         runnigMeanSpeed,
+		"runningTimeInMinutes":     runningTimeInMinutes,
+		"runningMeanSpeedModifier": runningMeanSpeedModifier,
+		"longRunBonus":             r.longRunBonus(baseCalories),
+		"calories":                 r.Calories(),
+	}
+}
+
+// Cadence возвращает каденс бега — количество шагов в минуту.
+// Формула расчета:
+// количество_шагов / время_тренировки_в_минутах
+func (r Running) Cadence() float64 {
+	timeOfTrainingInMinutes := r.Duration.Minutes()
+
+	if timeOfTrainingInMinutes == 0 {
+		return 0
+	}
+
+	return float64(r.Action) / timeOfTrainingInMinutes
+}
+
+// PredictTime возвращает прогноз времени на дистанции targetKm км по формуле Ригеля
+// на основе дистанции и времени этой пробежки.
+// Формула расчета:
+// время_тренировки * (целевая_дистанция_в_км / дистанция_в_км) ^ RiegelExponent
+// Формула рассчитана на пробежки с максимальным усилием и дистанции от 1.5 км до марафона;
+// прогноз для более длинных дистанций или по легкой пробежке будет слишком оптимистичным.
+// Для targetKm <= 0 или пробежки без дистанции возвращает 0.
+func (r Running) PredictTime(targetKm float64) time.Duration {
+	distance := r.distance()
+
+	if targetKm <= 0 || distance <= 0 {
+		return 0
+	}
+
+	return time.Duration(float64(r.Duration) * math.Pow(targetKm/distance, RiegelExponent))
+}
+
+// FastestKm возвращает время самого быстрого километра пробежки.
+// Пока данных по отрезкам нет, темп считается постоянным и результат равен среднему времени километра.
+// Для пробежек короче 1 км возвращает 0.
+func (r Running) FastestKm() time.Duration {
+	distance := r.distance()
+
+	if distance < 1 {
+		return 0
+	}
+
+	return time.Duration(float64(r.Duration) / distance)
+}
+
+// AverageGrade возвращает средний уклон трассы в процентах.
+// Формула расчета:
+// набор_высоты_в_м / дистанция_в_м * 100
+// Для тренировки без дистанции возвращает 0.
+func (r Running) AverageGrade() float64 {
+	return r.TrainingInfo().grade() * 100
+}
+
+// GradeAdjustedPace возвращает темп бега с поправкой на уклон (GAP) — темп, который потребовал бы
+// тех же усилий на ровной местности. Без набора высоты равен среднему темпу.
+func (r Running) GradeAdjustedPace() time.Duration {
+	return r.TrainingInfo().gradeAdjustedPace()
 }
 
 // TrainingInfo возвращает структуру InfoMessage с информацией о проведенной тренировке.
@@ -125,11 +404,72 @@ func (r Running) TrainingInfo() InfoMessage {
 	}
 }
 
+// CaloriesAtWeight возвращает количество килокалорий, которое потратил бы при беге пользователь с весом weight.
+// Остальные параметры тренировки не меняются. Для веса <= 0 возвращает 0.
+func (r Running) CaloriesAtWeight(weight float64) float64 {
+	if weight <= 0 {
+		return 0
+	}
+
+	r.Weight = weight
+	return r.Calories()
+}
+
+// WithDuration возвращает копию тренировки Running с продолжительностью d.
+// Остальные поля копии и исходная тренировка не меняются.
+func (r Running) WithDuration(d time.Duration) CaloriesCalculator {
+	r.Duration = d
+	return r
+}
+
+// WithDefaultWeight возвращает копию тренировки Running, в которой незаполненный вес
+// заменен на defaultKg. Если вес указан, копия совпадает с исходной тренировкой.
+// Исходная тренировка не меняется.
+func (r Running) WithDefaultWeight(defaultKg float64) CaloriesCalculator {
+	if r.Weight == 0 {
+		r.Weight = defaultKg
+	}
+	return r
+}
+
+// caloriesLine возвращает расход калорий при беге как линейную функцию продолжительности
+// при той же средней скорости: постоянной части нет, расход в час равен расходу за час тренировки.
+// Надбавка за длительный бег учитывается так, как она начислена за продолжительность этой тренировки.
+func (r Running) caloriesLine() (intercept, rate float64) {
+	return proportionalCaloriesLine(r.Calories(), r.Duration)
+}
+
+// BreakEven возвращает продолжительность, при которой тренировка Running и other
+// с их интенсивностью тратят одинаковое количество калорий, или NoBreakEven, если такой нет.
+func (r Running) BreakEven(other CaloriesCalculator) time.Duration {
+	return breakEven(r, other)
+}
+
+// CaloriesPerHour возвращает расход калорий тренировки Бег за час, см. InfoMessage.CaloriesPerHour.
+func (r Running) CaloriesPerHour() float64 {
+	return r.TrainingInfo().CaloriesPerHour()
+}
+
+// CaloriesForDistance возвращает расход калорий тренировки Бег на дистанции distanceKm км
+// с той же средней скоростью, см. InfoMessage.CaloriesForDistance.
+func (r Running) CaloriesForDistance(distanceKm float64) float64 {
+	return r.TrainingInfo().CaloriesForDistance(distanceKm)
+}
+
+// EnergySplit возвращает доли калорий тренировки Бег из жиров и углеводов, см. InfoMessage.EnergySplit.
+func (r Running) EnergySplit() (fatKcal, carbKcal float64) {
+	return r.TrainingInfo().EnergySplit()
+}
+
 // Константы для расчета потраченных килокалорий при ходьбе.
 const (
 	CaloriesWeightMultiplier      = 0.035 // коэффициент для веса
 	CaloriesSpeedHeightMultiplier = 0.029 // коэффициент для роста
 	KmHInMsec                     = 0.278 // коэффициент для перевода км/ч в м/с
+
+	WalkingUphillGradeMultiplier   = 10   // прибавка к расходу калорий на единицу уклона при подъеме
+	WalkingDownhillGradeMultiplier = 5    // снижение расхода калорий на единицу уклона при спуске
+	WalkingMaxDescentReduction     = 0.15 // максимальная доля, на которую спуск снижает расход калорий
 )
 
 // Walking структура описывающая тренировку Ходьба
@@ -138,12 +478,44 @@ type Walking struct {
 	Height float64
 }
 
+// IsZero сообщает, что тренировка Ходьба не заполнена.
+// Это переопределенный метод IsZero() из Training.
+func (w Walking) IsZero() bool {
+	return w.Training.IsZero() && w.Height == 0
+}
+
+// elevationModifier возвращает множитель расхода калорий при ходьбе с учетом набора высоты.
+// Уклон считается как набор_высоты_в_м / дистанция_в_м.
+// При подъеме множитель равен 1 + 10 * уклон, при спуске — 1 + 5 * уклон,
+// но не меньше 1 - 0.15, то есть спуск снижает расход калорий не более чем на 15%.
+// Без набора высоты или при нулевой дистанции множитель равен 1.
+func (w Walking) elevationModifier() float64 {
+	distanceInMetres := w.distance() * MInKm
+
+	if w.ElevationGain == 0 || distanceInMetres == 0 {
+		return 1
+	}
+
+	grade := w.ElevationGain / distanceInMetres
+
+	if grade > 0 {
+		return 1 + WalkingUphillGradeMultiplier*grade
+	}
+
+	return math.Max(1-WalkingMaxDescentReduction, 1+WalkingDownhillGradeMultiplier*grade)
+}
+
 // Calories возвращает количество потраченных килокалорий при ходьбе.
 // Формула расчета:
 // ((0.035 * вес_спортсмена_в_кг + (средняя_скорость_в_метрах_в_секунду**2 / рост_в_метрах)
-// * 0.029 * вес_спортсмена_в_кг) * время_тренировки_в_часах * мин_в_ч)
+// * 0.029 * вес_спортсмена_в_кг) * время_тренировки_в_часах * мин_в_ч) * множитель_набора_высоты
+// Если рост не указан, возвращает 0.
 // Это переопределенный метод Calories() из Training.
 func (w Walking) Calories() float64 {
+	if w.Height == 0 {
+		return 0
+	}
+
 	walkingMeanSpeedInMetresPerSecond := w.meanSpeed() * KmHInMsec
 	heightInMetres := float64(w.Height / CmInM)
 	trainingTimeInMinutes := w.Duration.Hours() * MinInHours
@@ -153,11 +525,50 @@ func (w Walking) Calories() float64 {
 
 	walkingSpeedModifier := math.Pow(walkingMeanSpeedInMetresPerSecond, 2) / heightInMetres
 
-	spentCaloriesWhileWalking := (firstWeightModifier + walkingSpeedModifier*secondWeightModifier) * trainingTimeInMinutes
+	spentCaloriesWhileWalking := (firstWeightModifier + walkingSpeedModifier*secondWeightModifier) * trainingTimeInMinutes *
+		w.elevationModifier()
 
 	return spentCaloriesWhileWalking
 }
 
+// CaloriesBreakdown возвращает промежуточные значения формулы расчета калорий при ходьбе.
+// Ключ "calories" содержит итоговое значение, совпадающее с Calories().
+// Если рост не указан, walkingSpeedModifier равен 0.
+func (w Walking) CaloriesBreakdown() map[string]float64 {
+	walkingMeanSpeedInMetresPerSecond := w.meanSpeed() * KmHInMsec
+	heightInMetres := float64(w.Height / CmInM)
+
+	walkingSpeedModifier := 0.0
+	if heightInMetres != 0 {
+		walkingSpeedModifier = math.Pow(walkingMeanSpeedInMetresPerSecond, 2) / heightInMetres
+	}
+
+	return map[string]float64{
+		"walkingMeanSpeedInMetresPerSecond": walkingMeanSpeedInMetresPerSecond,
+		"heightInMetres":                    heightInMetres,
+		"trainingTimeInMinutes":             w.Duration.Hours() * MinInHours,
+		"firstWeightModifier":               CaloriesWeightMultiplier * w.Weight,
+		"secondWeightModifier":              CaloriesSpeedHeightMultiplier * w.Weight,
+		"walkingSpeedModifier":              walkingSpeedModifier,
+		"elevationModifier":                 w.elevationModifier(),
+		"calories":                          w.Calories(),
+	}
+}
+
+// AverageGrade возвращает средний уклон трассы в процентах.
+// Формула расчета:
+// набор_высоты_в_м / дистанция_в_м * 100
+// Для тренировки без дистанции возвращает 0.
+func (w Walking) AverageGrade() float64 {
+	return w.TrainingInfo().grade() * 100
+}
+
+// GradeAdjustedPace возвращает темп ходьбы с поправкой на уклон (GAP) — темп, который потребовал бы
+// тех же усилий на ровной местности. Без набора высоты равен среднему темпу.
+func (w Walking) GradeAdjustedPace() time.Duration {
+	return w.TrainingInfo().gradeAdjustedPace()
+}
+
 // TrainingInfo возвращает структуру InfoMessage с информацией о проведенной тренировке.
 // Это переопределенный метод TrainingInfo() из Training.
 func (w Walking) TrainingInfo() InfoMessage {
@@ -170,6 +581,164 @@ func (w Walking) TrainingInfo() InfoMessage {
 	}
 }
 
+// CaloriesAtWeight возвращает количество килокалорий, которое потратил бы при ходьбе пользователь с весом weight.
+// Остальные параметры тренировки не меняются. Для веса <= 0 возвращает 0.
+func (w Walking) CaloriesAtWeight(weight float64) float64 {
+	if weight <= 0 {
+		return 0
+	}
+
+	w.Weight = weight
+	return w.Calories()
+}
+
+// WithDuration возвращает копию тренировки Walking с продолжительностью d.
+// Остальные поля копии и исходная тренировка не меняются.
+func (w Walking) WithDuration(d time.Duration) CaloriesCalculator {
+	w.Duration = d
+	return w
+}
+
+// WithDefaultWeight возвращает копию тренировки Walking, в которой незаполненный вес
+// заменен на defaultKg. Если вес указан, копия совпадает с исходной тренировкой.
+// Исходная тренировка не меняется.
+func (w Walking) WithDefaultWeight(defaultKg float64) CaloriesCalculator {
+	if w.Weight == 0 {
+		w.Weight = defaultKg
+	}
+	return w
+}
+
+// caloriesLine возвращает расход калорий при ходьбе как линейную функцию продолжительности
+// при той же средней скорости: постоянной части нет, расход в час равен расходу за час тренировки.
+func (w Walking) caloriesLine() (intercept, rate float64) {
+	return proportionalCaloriesLine(w.Calories(), w.Duration)
+}
+
+// BreakEven возвращает продолжительность, при которой тренировка Walking и other
+// с их интенсивностью тратят одинаковое количество калорий, или NoBreakEven, если такой нет.
+func (w Walking) BreakEven(other CaloriesCalculator) time.Duration {
+	return breakEven(w, other)
+}
+
+// CaloriesPerHour возвращает расход калорий тренировки Ходьба за час, см. InfoMessage.CaloriesPerHour.
+func (w Walking) CaloriesPerHour() float64 {
+	return w.TrainingInfo().CaloriesPerHour()
+}
+
+// CaloriesForDistance возвращает расход калорий тренировки Ходьба на дистанции distanceKm км
+// с той же средней скоростью, см. InfoMessage.CaloriesForDistance.
+func (w Walking) CaloriesForDistance(distanceKm float64) float64 {
+	return w.TrainingInfo().CaloriesForDistance(distanceKm)
+}
+
+// EnergySplit возвращает доли калорий тренировки Ходьба из жиров и углеводов, см. InfoMessage.EnergySplit.
+func (w Walking) EnergySplit() (fatKcal, carbKcal float64) {
+	return w.TrainingInfo().EnergySplit()
+}
+
+// NordicWalkingPoleMultiplier множитель расхода калорий при ходьбе с палками относительно обычной ходьбы.
+const NordicWalkingPoleMultiplier = 1.2
+
+// NordicWalking структура, описывающая тренировку Скандинавская ходьба.
+type NordicWalking struct {
+	Walking
+}
+
+// Calories возвращает количество потраченных килокалорий при скандинавской ходьбе.
+// Формула расчета:
+// калории_при_ходьбе * 1.2
+// Это переопределенный метод Calories() из Walking.
+func (n NordicWalking) Calories() float64 {
+	return n.Walking.Calories() * NordicWalkingPoleMultiplier
+}
+
+// CaloriesBreakdown возвращает промежуточные значения формулы расчета калорий при скандинавской ходьбе.
+// Ключ "calories" содержит итоговое значение, совпадающее с Calories().
+// Это переопределенный метод CaloriesBreakdown() из Walking.
+func (n NordicWalking) CaloriesBreakdown() map[string]float64 {
+	breakdown := n.Walking.CaloriesBreakdown()
+	breakdown["poleMultiplier"] = NordicWalkingPoleMultiplier
+	breakdown["calories"] = n.Calories()
+
+	return breakdown
+}
+
+// TrainingInfo возвращает структуру InfoMessage с информацией о проведенной тренировке.
+// Это переопределенный метод TrainingInfo() из Walking.
+func (n NordicWalking) TrainingInfo() InfoMessage {
+
+	return InfoMessage{
+		Training: n.Training,
+		Distance: n.distance(),
+		Speed:    n.meanSpeed(),
+		Calories: n.Calories(),
+	}
+}
+
+// CaloriesAtWeight возвращает количество килокалорий, которое потратил бы при скандинавской ходьбе
+// пользователь с весом weight. Остальные параметры тренировки не меняются. Для веса <= 0 возвращает 0.
+// Это переопределенный метод CaloriesAtWeight() из Walking.
+func (n NordicWalking) CaloriesAtWeight(weight float64) float64 {
+	if weight <= 0 {
+		return 0
+	}
+
+	n.Weight = weight
+	return n.Calories()
+}
+
+// WithDuration возвращает копию тренировки NordicWalking с продолжительностью d.
+// Остальные поля копии и исходная тренировка не меняются.
+// Это переопределенный метод WithDuration() из Walking.
+func (n NordicWalking) WithDuration(d time.Duration) CaloriesCalculator {
+	n.Duration = d
+	return n
+}
+
+// WithDefaultWeight возвращает копию тренировки NordicWalking, в которой незаполненный вес
+// заменен на defaultKg. Если вес указан, копия совпадает с исходной тренировкой.
+// Исходная тренировка не меняется.
+// Это переопределенный метод WithDefaultWeight() из Walking.
+func (n NordicWalking) WithDefaultWeight(defaultKg float64) CaloriesCalculator {
+	if n.Weight == 0 {
+		n.Weight = defaultKg
+	}
+	return n
+}
+
+// caloriesLine возвращает расход калорий при скандинавской ходьбе как линейную функцию продолжительности
+// при той же средней скорости. Это переопределенный метод caloriesLine() из Walking.
+func (n NordicWalking) caloriesLine() (intercept, rate float64) {
+	return proportionalCaloriesLine(n.Calories(), n.Duration)
+}
+
+// BreakEven возвращает продолжительность, при которой тренировка NordicWalking и other
+// с их интенсивностью тратят одинаковое количество калорий, или NoBreakEven, если такой нет.
+// Это переопределенный метод BreakEven() из Walking.
+func (n NordicWalking) BreakEven(other CaloriesCalculator) time.Duration {
+	return breakEven(n, other)
+}
+
+// CaloriesPerHour возвращает расход калорий тренировки Скандинавская ходьба за час, см. InfoMessage.CaloriesPerHour.
+// Это переопределенный метод CaloriesPerHour() из Walking.
+func (n NordicWalking) CaloriesPerHour() float64 {
+	return n.TrainingInfo().CaloriesPerHour()
+}
+
+// CaloriesForDistance возвращает расход калорий тренировки Скандинавская ходьба на дистанции distanceKm км
+// с той же средней скоростью, см. InfoMessage.CaloriesForDistance.
+// Это переопределенный метод CaloriesForDistance() из Walking.
+func (n NordicWalking) CaloriesForDistance(distanceKm float64) float64 {
+	return n.TrainingInfo().CaloriesForDistance(distanceKm)
+}
+
+// EnergySplit возвращает доли калорий тренировки Скандинавская ходьба из жиров и углеводов, см. InfoMessage.EnergySplit.
+// Это переопределенный метод EnergySplit() из Walking.
+func (n NordicWalking) EnergySplit() (fatKcal, carbKcal float64) {
+	return n.TrainingInfo().EnergySplit()
+}
+
 // Константы для расчета потраченных килокалорий при плавании.
 const (
 	SwimmingLenStep                  = 1.38 // длина одного гребка
@@ -177,25 +746,113 @@ const (
 	SwimmingCaloriesWeightMultiplier = 2    // множитель веса пользователя
 )
 
+// PoolSet описывает серию заплывов в бассейне одной длины.
+type PoolSet struct {
+	LengthPool int // длина бассейна в м
+	CountPool  int // сколько раз пользователь переплыл бассейн
+}
+
 // Swimming структура, описывающая тренировку Плавание
 type Swimming struct {
 	Training
 	LengthPool int
 	CountPool  int
+	PoolSets   []PoolSet // серии в бассейнах разной длины, если заданы, то LengthPool и CountPool не учитываются
+
+	RestBetweenSets     time.Duration // отдых между сериями, не входит в активное время плавания
+	CaloriesIncludeRest bool          // учитывать ли отдых между сериями при расчете калорий
+}
+
+// IsZero сообщает, что тренировка Плавание не заполнена.
+// Это переопределенный метод IsZero() из Training.
+func (s Swimming) IsZero() bool {
+	return s.Training.IsZero() && s.LengthPool == 0 && s.CountPool == 0 && len(s.PoolSets) == 0 &&
+		s.RestBetweenSets == 0 && !s.CaloriesIncludeRest
+}
+
+// poolSets возвращает серии заплывов тренировки.
+// Если PoolSets не заданы, тренировка считается одной серией из LengthPool и CountPool.
+func (s Swimming) poolSets() []PoolSet {
+	if len(s.PoolSets) == 0 {
+		return []PoolSet{{LengthPool: s.LengthPool, CountPool: s.CountPool}}
+	}
+
+	return s.PoolSets
+}
+
+// poolDistance возвращает суммарную дистанцию по всем сериям в км.
+// Формула расчета:
+// сумма(длина_бассейна * количество_пересечений) / м_в_км
+func (s Swimming) poolDistance() float64 {
+	totalMetres := 0
+	for _, set := range s.poolSets() {
+		totalMetres += set.LengthPool * set.CountPool
+	}
+
+	return float64(totalMetres) / MInKm
+}
+
+// distance возвращает дистанцию плавания в км: суммарную дистанцию по сериям, если заданы PoolSets,
+// и дистанцию по гребкам Action * LenStep для тренировки в одном бассейне.
+// Это переопределенный метод distance() из Training.
+func (s Swimming) distance() float64 {
+	if len(s.PoolSets) == 0 {
+		return s.Training.distance()
+	}
+
+	return s.poolDistance()
+}
+
+// activeDuration возвращает активное время плавания — продолжительность тренировки без отдыха между сериями.
+// Формула расчета:
+// продолжительность_тренировки - отдых_между_сериями * (количество_серий - 1)
+func (s Swimming) activeDuration() time.Duration {
+	restCount := len(s.poolSets()) - 1
+	activeDuration := s.Duration - s.RestBetweenSets*time.Duration(restCount)
+
+	if activeDuration < 0 {
+		return 0
+	}
+
+	return activeDuration
+}
+
+// MovingTimeRatio возвращает долю активного времени плавания от продолжительности тренировки.
+// Формула расчета:
+// активное_время / продолжительность_тренировки
+// Без отдыха между сериями или при нулевой продолжительности возвращает 1.
+// Это переопределенный метод MovingTimeRatio() из Training.
+func (s Swimming) MovingTimeRatio() float64 {
+	if s.Duration == 0 {
+		return 1
+	}
+
+	return float64(s.activeDuration()) / float64(s.Duration)
+}
+
+// caloriesDuration возвращает время, по которому считаются калории при плавании:
+// полную продолжительность, если CaloriesIncludeRest, и активное время в остальных случаях.
+func (s Swimming) caloriesDuration() time.Duration {
+	if s.CaloriesIncludeRest {
+		return s.Duration
+	}
+
+	return s.activeDuration()
 }
 
 // meanSpeed возвращает среднюю скорость при плавании.
+// Скорость считается по активному времени, без отдыха между сериями.
 // Формула расчета:
-// длина_бассейна * количество_пересечений / м_в_км / продолжительность_тренировки_в_часах
-// Это переопределенный метод Calories() из Training.
+// сумма(длина_бассейна * количество_пересечений) / м_в_км / активное_время_в_часах
+// Это переопределенный метод meanSpeed() из Training.
 func (s Swimming) meanSpeed() float64 {
-	timeOfTrainingInHours := s.Duration.Hours()
+	timeOfTrainingInHours := s.activeDuration().Hours()
 
 	if timeOfTrainingInHours == 0 {
 		return 0
 	}
 
-	meanSpeed := float64(s.LengthPool) * float64(s.CountPool) / MInKm / timeOfTrainingInHours
+	meanSpeed := s.poolDistance() / timeOfTrainingInHours
 
 	return meanSpeed
 }
@@ -203,16 +860,31 @@ func (s Swimming) meanSpeed() float64 {
 // Calories возвращает количество калорий, потраченных при плавании.
 // Формула расчета:
 // (средняя_скорость_в_км/ч + 1.1) * 2 * вес_спортсмена_в_кг * время_тренеровки_в_часах
+// Время тренировки — активное время, а при CaloriesIncludeRest — полная продолжительность.
 // Это переопределенный метод Calories() из Training.
 func (s Swimming) Calories() float64 {
 	swimmingMeanSpeed := s.meanSpeed()
-	trainingTime := s.Duration.Hours()
+	trainingTime := s.caloriesDuration().Hours()
 	spentCaloriesWhileSwimming := (swimmingMeanSpeed + SwimmingCaloriesMeanSpeedShift) *
 		SwimmingCaloriesWeightMultiplier * s.Weight * trainingTime
 
 	return spentCaloriesWhileSwimming
 }
 
+// CaloriesBreakdown возвращает промежуточные значения формулы расчета калорий при плавании.
+// Ключ "calories" содержит итоговое значение, совпадающее с Calories().
+func (s Swimming) CaloriesBreakdown() map[string]float64 {
+	swimmingMeanSpeed := s.meanSpeed()
+
+	return map[string]float64{
+		"swimmingMeanSpeed":     swimmingMeanSpeed,
+		"trainingTimeInHours":   s.caloriesDuration().Hours(),
+		"swimmingSpeedModifier": swimmingMeanSpeed + SwimmingCaloriesMeanSpeedShift,
+		"weightModifier":        SwimmingCaloriesWeightMultiplier * s.Weight,
+		"calories":              s.Calories(),
+	}
+}
+
 // TrainingInfo returns info about swimming training.
 // Это переопределенный метод TrainingInfo() из Training.
 func (s Swimming) TrainingInfo() InfoMessage {
@@ -225,6 +897,358 @@ func (s Swimming) TrainingInfo() InfoMessage {
 	}
 }
 
+// CaloriesAtWeight возвращает количество килокалорий, которое потратил бы при плавании пользователь с весом weight.
+// Остальные параметры тренировки не меняются. Для веса <= 0 возвращает 0.
+func (s Swimming) CaloriesAtWeight(weight float64) float64 {
+	if weight <= 0 {
+		return 0
+	}
+
+	s.Weight = weight
+	return s.Calories()
+}
+
+// WithDuration возвращает копию тренировки Swimming с продолжительностью d.
+// Остальные поля копии и исходная тренировка не меняются.
+func (s Swimming) WithDuration(d time.Duration) CaloriesCalculator {
+	s.Duration = d
+	return s
+}
+
+// WithDefaultWeight возвращает копию тренировки Swimming, в которой незаполненный вес
+// заменен на defaultKg. Если вес указан, копия совпадает с исходной тренировкой.
+// Исходная тренировка не меняется.
+func (s Swimming) WithDefaultWeight(defaultKg float64) CaloriesCalculator {
+	if s.Weight == 0 {
+		s.Weight = defaultKg
+	}
+	return s
+}
+
+// caloriesLine возвращает расход калорий при плавании как линейную функцию продолжительности
+// при той же средней скорости и том же отдыхе между сериями.
+// Формула расчета:
+// расход_в_час = (средняя_скорость_в_км/ч + 1.1) * 2 * вес_спортсмена_в_кг,
+// постоянная_часть = -расход_в_час * отдых_между_сериями_в_часах * (количество_серий - 1),
+// если отдых не входит в расчет калорий, и 0 при CaloriesIncludeRest.
+func (s Swimming) caloriesLine() (intercept, rate float64) {
+	rate = (s.meanSpeed() + SwimmingCaloriesMeanSpeedShift) * SwimmingCaloriesWeightMultiplier * s.Weight
+	if s.CaloriesIncludeRest {
+		return 0, rate
+	}
+
+	restHours := (s.RestBetweenSets * time.Duration(len(s.poolSets())-1)).Hours()
+
+	return -rate * restHours, rate
+}
+
+// BreakEven возвращает продолжительность, при которой тренировка Swimming и other
+// с их интенсивностью тратят одинаковое количество калорий, или NoBreakEven, если такой нет.
+func (s Swimming) BreakEven(other CaloriesCalculator) time.Duration {
+	return breakEven(s, other)
+}
+
+// CaloriesPerHour возвращает расход калорий тренировки Плавание за час, см. InfoMessage.CaloriesPerHour.
+func (s Swimming) CaloriesPerHour() float64 {
+	return s.TrainingInfo().CaloriesPerHour()
+}
+
+// CaloriesForDistance возвращает расход калорий тренировки Плавание на дистанции distanceKm км
+// с той же средней скоростью, см. InfoMessage.CaloriesForDistance.
+func (s Swimming) CaloriesForDistance(distanceKm float64) float64 {
+	return s.TrainingInfo().CaloriesForDistance(distanceKm)
+}
+
+// EnergySplit возвращает доли калорий тренировки Плавание из жиров и углеводов, см. InfoMessage.EnergySplit.
+func (s Swimming) EnergySplit() (fatKcal, carbKcal float64) {
+	return s.TrainingInfo().EnergySplit()
+}
+
+// Константы для расчета потраченных килокалорий на велотренажере.
+const (
+	StationaryBikeWattsPerLevel    = 10 // мощность в ваттах на один уровень сопротивления при эталонном каденсе
+	StationaryBikeReferenceCadence = 60 // эталонный каденс в оборотах педалей в минуту
+)
+
+// StationaryBike структура, описывающая тренировку на велотренажере.
+// Action — количество оборотов педалей, дистанция и скорость не считаются.
+type StationaryBike struct {
+	Training
+	ResistanceLevel int // уровень сопротивления велотренажера
+}
+
+// IsZero сообщает, что тренировка на велотренажере не заполнена.
+// Это переопределенный метод IsZero() из Training.
+func (b StationaryBike) IsZero() bool {
+	return b.Training.IsZero() && b.ResistanceLevel == 0
+}
+
+// distance возвращает 0: на велотренажере дистанция не преодолевается.
+// Это переопределенный метод distance() из Training.
+func (b StationaryBike) distance() float64 {
+	return 0
+}
+
+// meanSpeed возвращает 0: на велотренажере скорость не считается.
+// Это переопределенный метод meanSpeed() из Training.
+func (b StationaryBike) meanSpeed() float64 {
+	return 0
+}
+
+// Cadence возвращает каденс — количество оборотов педалей в минуту.
+// Формула расчета:
+// количество_оборотов / время_тренировки_в_минутах
+func (b StationaryBike) Cadence() float64 {
+	timeOfTrainingInMinutes := b.Duration.Minutes()
+
+	if timeOfTrainingInMinutes == 0 {
+		return 0
+	}
+
+	return float64(b.Action) / timeOfTrainingInMinutes
+}
+
+// power возвращает мощность педалирования в ваттах.
+// Каждый уровень сопротивления дает 10 Вт при каденсе 60 об/мин, мощность растет пропорционально каденсу.
+// Формула расчета:
+// 10 * уровень_сопротивления * каденс / 60
+func (b StationaryBike) power() float64 {
+	return StationaryBikeWattsPerLevel * float64(b.ResistanceLevel) * b.Cadence() / StationaryBikeReferenceCadence
+}
+
+// Calories возвращает количество потраченных килокалорий на велотренажере.
+// Формула расчета:
+// мощность_в_ваттах / (69.78 * 0.24) * время_тренировки_в_минутах
+// Расход калорий зависит от мощности, а не от веса пользователя.
+// Это переопределенный метод Calories() из Training.
+func (b StationaryBike) Calories() float64 {
+	caloriesPerMinute := b.power() / (WattsPerKcalPerMin * MechanicalEfficiency)
+
+	return caloriesPerMinute * b.Duration.Minutes()
+}
+
+// CaloriesBreakdown возвращает промежуточные значения формулы расчета калорий на велотренажере.
+// Ключ "calories" содержит итоговое значение, совпадающее с Calories().
+func (b StationaryBike) CaloriesBreakdown() map[string]float64 {
+	return map[string]float64{
+		"cadence":               b.Cadence(),
+		"power":                 b.power(),
+		"trainingTimeInMinutes": b.Duration.Minutes(),
+		"calories":              b.Calories(),
+	}
+}
+
+// TrainingInfo возвращает структуру InfoMessage с информацией о проведенной тренировке.
+// Это переопределенный метод TrainingInfo() из Training.
+func (b StationaryBike) TrainingInfo() InfoMessage {
+
+	return InfoMessage{
+		Training: b.Training,
+		Distance: b.distance(),
+		Speed:    b.meanSpeed(),
+		Calories: b.Calories(),
+	}
+}
+
+// CaloriesAtWeight возвращает количество килокалорий, которое потратил бы на велотренажере пользователь с весом weight.
+// Расход калорий на велотренажере от веса не зависит. Для веса <= 0 возвращает 0.
+func (b StationaryBike) CaloriesAtWeight(weight float64) float64 {
+	if weight <= 0 {
+		return 0
+	}
+
+	b.Weight = weight
+	return b.Calories()
+}
+
+// WithDuration возвращает копию тренировки StationaryBike с продолжительностью d.
+// Остальные поля копии и исходная тренировка не меняются.
+func (b StationaryBike) WithDuration(d time.Duration) CaloriesCalculator {
+	b.Duration = d
+	return b
+}
+
+// WithDefaultWeight возвращает копию тренировки StationaryBike, в которой незаполненный вес
+// заменен на defaultKg. Если вес указан, копия совпадает с исходной тренировкой.
+// Исходная тренировка не меняется.
+func (b StationaryBike) WithDefaultWeight(defaultKg float64) CaloriesCalculator {
+	if b.Weight == 0 {
+		b.Weight = defaultKg
+	}
+	return b
+}
+
+// caloriesLine возвращает расход калорий на велотренажере как линейную функцию продолжительности
+// при той же мощности: постоянной части нет, расход в час равен расходу за час тренировки.
+func (b StationaryBike) caloriesLine() (intercept, rate float64) {
+	return proportionalCaloriesLine(b.Calories(), b.Duration)
+}
+
+// BreakEven возвращает продолжительность, при которой тренировка StationaryBike и other
+// с их интенсивностью тратят одинаковое количество калорий, или NoBreakEven, если такой нет.
+func (b StationaryBike) BreakEven(other CaloriesCalculator) time.Duration {
+	return breakEven(b, other)
+}
+
+// CaloriesPerHour возвращает расход калорий тренировки на велотренажере за час, см. InfoMessage.CaloriesPerHour.
+func (b StationaryBike) CaloriesPerHour() float64 {
+	return b.TrainingInfo().CaloriesPerHour()
+}
+
+// CaloriesForDistance возвращает расход калорий тренировки на велотренажере на дистанции distanceKm км
+// с той же средней скоростью, см. InfoMessage.CaloriesForDistance.
+func (b StationaryBike) CaloriesForDistance(distanceKm float64) float64 {
+	return b.TrainingInfo().CaloriesForDistance(distanceKm)
+}
+
+// EnergySplit возвращает доли калорий тренировки на велотренажере из жиров и углеводов, см. InfoMessage.EnergySplit.
+func (b StationaryBike) EnergySplit() (fatKcal, carbKcal float64) {
+	return b.TrainingInfo().EnergySplit()
+}
+
+// Константы для расчета потраченных килокалорий на силовой тренировке.
+const (
+	StrengthMET         = 3.5  // метаболический эквивалент силовой тренировки средней интенсивности
+	StrengthLiftHeightM = 0.5  // средняя высота подъема веса за одно повторение в м
+	GravityAcceleration = 9.81 // ускорение свободного падения в м/с²
+	JoulesInKcal        = 4184 // количество джоулей в одной килокалории
+	MetKcalPerKgPerHour = 1    // расход килокалорий на кг веса за час при нагрузке в 1 MET
+)
+
+// Strength структура, описывающая силовую тренировку.
+// Дистанция и скорость не считаются.
+type Strength struct {
+	Training
+	Sets   int     // количество подходов
+	Reps   int     // количество повторений в подходе
+	LoadKg float64 // вес снаряда в кг
+}
+
+// IsZero сообщает, что силовая тренировка не заполнена.
+// Это переопределенный метод IsZero() из Training.
+func (s Strength) IsZero() bool {
+	return s.Training.IsZero() && s.Sets == 0 && s.Reps == 0 && s.LoadKg == 0
+}
+
+// distance возвращает 0: на силовой тренировке дистанция не преодолевается.
+// Это переопределенный метод distance() из Training.
+func (s Strength) distance() float64 {
+	return 0
+}
+
+// meanSpeed возвращает 0: на силовой тренировке скорость не считается.
+// Это переопределенный метод meanSpeed() из Training.
+func (s Strength) meanSpeed() float64 {
+	return 0
+}
+
+// totalWorkKg возвращает суммарный поднятый вес за тренировку в кг.
+// Формула расчета:
+// количество_подходов * количество_повторений * вес_снаряда_в_кг
+func (s Strength) totalWorkKg() float64 {
+	return float64(s.Sets*s.Reps) * s.LoadKg
+}
+
+// Calories возвращает количество потраченных килокалорий на силовой тренировке.
+// Расход складывается из базового расхода по MET и энергии на подъем снаряда.
+// Формула расчета:
+// 3.5 * вес_спортсмена_в_кг * время_тренировки_в_часах +
+// суммарный_поднятый_вес_в_кг * 9.81 * 0.5 / 0.24 / дж_в_ккал
+// Это переопределенный метод Calories() из Training.
+func (s Strength) Calories() float64 {
+	metCalories := StrengthMET * MetKcalPerKgPerHour * s.Weight * s.Duration.Hours()
+
+	return metCalories + s.workCalories()
+}
+
+// workCalories возвращает энергию на подъем снаряда в ккал. Она не зависит от продолжительности тренировки.
+// Формула расчета:
+// суммарный_поднятый_вес_в_кг * 9.81 * 0.5 / 0.24 / дж_в_ккал
+func (s Strength) workCalories() float64 {
+	return s.totalWorkKg() * GravityAcceleration * StrengthLiftHeightM / MechanicalEfficiency / JoulesInKcal
+}
+
+// CaloriesBreakdown возвращает промежуточные значения формулы расчета калорий на силовой тренировке.
+// Ключ "calories" содержит итоговое значение, совпадающее с Calories().
+func (s Strength) CaloriesBreakdown() map[string]float64 {
+	return map[string]float64{
+		"metCalories":  StrengthMET * MetKcalPerKgPerHour * s.Weight * s.Duration.Hours(),
+		"totalWorkKg":  s.totalWorkKg(),
+		"workCalories": s.workCalories(),
+		"calories":     s.Calories(),
+	}
+}
+
+// TrainingInfo возвращает структуру InfoMessage с информацией о проведенной тренировке.
+// Это переопределенный метод TrainingInfo() из Training.
+func (s Strength) TrainingInfo() InfoMessage {
+
+	return InfoMessage{
+		Training: s.Training,
+		Distance: s.distance(),
+		Speed:    s.meanSpeed(),
+		Calories: s.Calories(),
+	}
+}
+
+// CaloriesAtWeight возвращает количество килокалорий, которое потратил бы на силовой тренировке
+// пользователь с весом weight. Остальные параметры тренировки не меняются. Для веса <= 0 возвращает 0.
+func (s Strength) CaloriesAtWeight(weight float64) float64 {
+	if weight <= 0 {
+		return 0
+	}
+
+	s.Weight = weight
+	return s.Calories()
+}
+
+// WithDuration возвращает копию тренировки Strength с продолжительностью d.
+// Остальные поля копии и исходная тренировка не меняются.
+func (s Strength) WithDuration(d time.Duration) CaloriesCalculator {
+	s.Duration = d
+	return s
+}
+
+// WithDefaultWeight возвращает копию тренировки Strength, в которой незаполненный вес
+// заменен на defaultKg. Если вес указан, копия совпадает с исходной тренировкой.
+// Исходная тренировка не меняется.
+func (s Strength) WithDefaultWeight(defaultKg float64) CaloriesCalculator {
+	if s.Weight == 0 {
+		s.Weight = defaultKg
+	}
+	return s
+}
+
+// caloriesLine возвращает расход калорий на силовой тренировке как линейную функцию продолжительности
+// при тех же подходах: постоянная часть — энергия на подъем снаряда, расход в час — расход по MET.
+// Формула расчета:
+// постоянная_часть = энергия_на_подъем_снаряда, расход_в_час = 3.5 * вес_спортсмена_в_кг
+func (s Strength) caloriesLine() (intercept, rate float64) {
+	return s.workCalories(), StrengthMET * MetKcalPerKgPerHour * s.Weight
+}
+
+// BreakEven возвращает продолжительность, при которой тренировка Strength и other
+// с их интенсивностью тратят одинаковое количество калорий, или NoBreakEven, если такой нет.
+func (s Strength) BreakEven(other CaloriesCalculator) time.Duration {
+	return breakEven(s, other)
+}
+
+// CaloriesPerHour возвращает расход калорий силовой тренировки за час, см. InfoMessage.CaloriesPerHour.
+func (s Strength) CaloriesPerHour() float64 {
+	return s.TrainingInfo().CaloriesPerHour()
+}
+
+// CaloriesForDistance возвращает расход калорий силовой тренировки на дистанции distanceKm км
+// с той же средней скоростью, см. InfoMessage.CaloriesForDistance.
+func (s Strength) CaloriesForDistance(distanceKm float64) float64 {
+	return s.TrainingInfo().CaloriesForDistance(distanceKm)
+}
+
+// EnergySplit возвращает доли калорий силовой тренировки из жиров и углеводов, см. InfoMessage.EnergySplit.
+func (s Strength) EnergySplit() (fatKcal, carbKcal float64) {
+	return s.TrainingInfo().EnergySplit()
+}
+
 // ReadData возвращает информацию о проведенной тренировке.
 func ReadData(training CaloriesCalculator) string {
 	calories := training.Calories()
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// checkFinite сообщает об ошибке, если value равно NaN или бесконечности.
+func checkFinite(t *testing.T, name string, value float64) {
+	t.Helper()
+
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		t.Errorf("%s = %v, want finite value", name, value)
+	}
+}
+
+// zeroValueTraining методы, общие для всех видов тренировок.
+type zeroValueTraining interface {
+	CaloriesCalculator
+	IsZero() bool
+	CaloriesBreakdown() map[string]float64
+	CaloriesAtWeight(weight float64) float64
+	MovingTimeRatio() float64
+	WithDuration(d time.Duration) CaloriesCalculator
+	WithDefaultWeight(defaultKg float64) CaloriesCalculator
+	MarshalBinary() ([]byte, error)
+	CaloriesPerHour() float64
+	CaloriesForDistance(distanceKm float64) float64
+	EnergySplit() (fatKcal, carbKcal float64)
+}
+
+func TestZeroValueTrainings(t *testing.T) {
+	filled := Training{Action: 1000, LenStep: LenStep, Duration: time.Hour, Weight: 70}
+
+	tests := []struct {
+		name     string
+		training zeroValueTraining
+		nonZero  zeroValueTraining
+	}{
+		{name: "Running", training: Running{}, nonZero: Running{LongRunBonus: true}},
+		{name: "Walking", training: Walking{}, nonZero: Walking{Training: filled}},
+		{name: "NordicWalking", training: NordicWalking{}, nonZero: NordicWalking{Walking: Walking{Height: 180}}},
+		{name: "Swimming", training: Swimming{}, nonZero: Swimming{PoolSets: []PoolSet{{LengthPool: 25, CountPool: 4}}}},
+		{name: "StationaryBike", training: StationaryBike{}, nonZero: StationaryBike{ResistanceLevel: 5}},
+		{name: "Strength", training: Strength{}, nonZero: Strength{Training: filled, Sets: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.training.IsZero() {
+				t.Error("IsZero() = false for zero value, want true")
+			}
+			if tt.nonZero.IsZero() {
+				t.Error("IsZero() = true for filled training, want false")
+			}
+
+			if got := tt.training.Calories(); got != 0 {
+				t.Errorf("Calories() = %v, want 0", got)
+			}
+			checkFinite(t, "CaloriesAtWeight(70)", tt.training.CaloriesAtWeight(70))
+			checkFinite(t, "MovingTimeRatio()", tt.training.MovingTimeRatio())
+			checkFinite(t, "CaloriesPerHour()", tt.training.CaloriesPerHour())
+			checkFinite(t, "CaloriesForDistance(5)", tt.training.CaloriesForDistance(5))
+
+			fat, carb := tt.training.EnergySplit()
+			checkFinite(t, "EnergySplit() fat", fat)
+			checkFinite(t, "EnergySplit() carb", carb)
+
+			info := tt.training.TrainingInfo()
+			if info.Distance != 0 {
+				t.Errorf("TrainingInfo().Distance = %v, want 0", info.Distance)
+			}
+			checkFinite(t, "TrainingInfo().Speed", info.Speed)
+			if info.Calories != 0 {
+				t.Errorf("TrainingInfo().Calories = %v, want 0", info.Calories)
+			}
+
+			for key, value := range tt.training.CaloriesBreakdown() {
+				checkFinite(t, "CaloriesBreakdown()["+key+"]", value)
+			}
+
+			checkFinite(t, "WithDuration(time.Hour).Calories()", tt.training.WithDuration(time.Hour).Calories())
+			checkFinite(t, "WithDefaultWeight(70).Calories()", tt.training.WithDefaultWeight(70).Calories())
+
+			data, err := tt.training.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error = %v", err)
+			}
+			if _, err := UnmarshalTraining(data); err != nil {
+				t.Errorf("UnmarshalTraining() error = %v", err)
+			}
+
+			if cadence, ok := tt.training.(interface{ Cadence() float64 }); ok {
+				checkFinite(t, "Cadence()", cadence.Cadence())
+			}
+			if running, ok := tt.training.(Running); ok {
+				if got := running.PredictTime(10); got < 0 {
+					t.Errorf("PredictTime(10) = %v, want non-negative", got)
+				}
+				if got := running.FastestKm(); got < 0 {
+					t.Errorf("FastestKm() = %v, want non-negative", got)
+				}
+			}
+			if graded, ok := tt.training.(interface {
+				AverageGrade() float64
+				GradeAdjustedPace() time.Duration
+			}); ok {
+				checkFinite(t, "AverageGrade()", graded.AverageGrade())
+				if got := graded.GradeAdjustedPace(); got < 0 {
+					t.Errorf("GradeAdjustedPace() = %v, want non-negative", got)
+				}
+			}
+
+			checkZeroValueInfo(t, info)
+
+			if ReadData(tt.training) == "" {
+				t.Error("ReadData() is empty")
+			}
+		})
+	}
+}
+
+// checkZeroValueInfo вызывает показатели InfoMessage для нулевой тренировки
+// и проверяет, что они конечны, а продолжительности не отрицательны.
+func checkZeroValueInfo(t *testing.T, info InfoMessage) {
+	t.Helper()
+
+	fat, carb := info.EnergySplit()
+	low, high := info.CaloriesRange()
+	for name, value := range map[string]float64{
+		"AveragePowerWatts()":        info.AveragePowerWatts(),
+		"PerceivedExertion()":        info.PerceivedExertion(),
+		"EnergySplit() fat":          fat,
+		"EnergySplit() carb":         carb,
+		"NetCalories()":              info.NetCalories(),
+		"CaloriesForDistance(5)":     info.CaloriesForDistance(5),
+		"CO2SavedGramsVsCar()":       info.CO2SavedGramsVsCar(),
+		"IntensityFactor(12)":        info.IntensityFactor(12),
+		"EstimatedFluidLossMl()":     info.EstimatedFluidLossMl(),
+		"CaloriesRange() low":        low,
+		"CaloriesRange() high":       high,
+		"DistanceForCalories(300)":   info.DistanceForCalories(300),
+		"CaloriesToMilestone(1000)":  info.CaloriesToMilestone(1000),
+		"RelativeEffort(info)":       info.RelativeEffort(info),
+		"PowerToWeight()":            info.PowerToWeight(),
+		"CaloriesPerHour()":          info.CaloriesPerHour(),
+		"MileageContribution(100)":   info.MileageContribution(100),
+		"EstimatedVO2()":             info.EstimatedVO2(),
+		"EquivalentFlatDistance()":   info.EquivalentFlatDistance(),
+		"float64(EquivalentSteps())": float64(info.EquivalentSteps()),
+		"float64(PaceZone(5m))":      float64(info.PaceZone(5 * time.Minute)),
+	} {
+		checkFinite(t, name, value)
+	}
+
+	for food, amount := range info.FoodEquivalent() {
+		checkFinite(t, "FoodEquivalent()["+food+"]", amount)
+	}
+
+	for name, value := range map[string]time.Duration{
+		"RecommendedRecovery()":   info.RecommendedRecovery(),
+		"Pace()":                  info.Pace(),
+		"EstimateThresholdPace()": info.EstimateThresholdPace(),
+	} {
+		if value < 0 {
+			t.Errorf("%s = %v, want non-negative", name, value)
+		}
+	}
+
+	info.HydrationPlan()
+	info.SuggestReclassification()
+	if _, err := info.MarshalJSON(); err != nil {
+		t.Errorf("MarshalJSON() error = %v", err)
+	}
+	for name, text := range map[string]string{
+		"String()":        info.String(),
+		"CompactEncode()": info.CompactEncode(),
+		"Narrate()":       info.Narrate(),
+		"EmojiSummary()":  info.EmojiSummary(),
+	} {
+		if text == "" {
+			t.Errorf("%s is empty", name)
+		}
+	}
+}
+
+func TestWalkingElevation(t *testing.T) {
+	flat := Walking{
+		Training: Training{
+			TrainingType: WalkingType,
+			Action:       10000, // 6.5 км
+			LenStep:      LenStep,
+			Duration:     time.Hour,
+			Weight:       80,
+		},
+		Height: 180,
+	}
+	flatCalories := flat.Calories()
+
+	tests := []struct {
+		name          string
+		elevationGain float64
+		wantModifier  float64
+	}{
+		{name: "flat", elevationGain: 0, wantModifier: 1},
+		{name: "uphill 1%", elevationGain: 65, wantModifier: 1.1},
+		{name: "downhill 1%", elevationGain: -65, wantModifier: 0.95},
+		{name: "steep downhill is floored at 15%", elevationGain: -650, wantModifier: 0.85},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			walking := flat
+			walking.ElevationGain = tt.elevationGain
+
+			if got := walking.TrainingInfo().Distance; got != flat.TrainingInfo().Distance {
+				t.Fatalf("Distance = %v, want %v", got, flat.TrainingInfo().Distance)
+			}
+
+			if got := walking.elevationModifier(); math.Abs(got-tt.wantModifier) > 1e-9 {
+				t.Errorf("elevationModifier() = %v, want %v", got, tt.wantModifier)
+			}
+
+			want := flatCalories * tt.wantModifier
+			if got := walking.Calories(); math.Abs(got-want) > 1e-9 {
+				t.Errorf("Calories() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestNordicWalkingCaloriesComparedToWalking(t *testing.T) {
+	walking := Walking{
+		Training: Training{
+			TrainingType: WalkingType,
+			Action:       10000,
+			LenStep:      LenStep,
+			Duration:     time.Hour,
+			Weight:       80,
+		},
+		Height: 180,
+	}
+	nordicWalking := NordicWalking{Walking: walking}
+	nordicWalking.TrainingType = NordicWalkingType
+
+	walkingInfo := walking.TrainingInfo()
+	nordicInfo := nordicWalking.TrainingInfo()
+
+	if nordicInfo.Distance != walkingInfo.Distance || nordicInfo.Speed != walkingInfo.Speed {
+		t.Errorf("distance and speed = %v, %v, want the same as walking %v, %v",
+			nordicInfo.Distance, nordicInfo.Speed, walkingInfo.Distance, walkingInfo.Speed)
+	}
+
+	want := walking.Calories() * NordicWalkingPoleMultiplier
+	if got := nordicWalking.Calories(); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Calories() = %v, want %v", got, want)
+	}
+	if got := nordicInfo.Calories; math.Abs(got-want) > 1e-9 {
+		t.Errorf("TrainingInfo().Calories = %v, want %v", got, want)
+	}
+	if nordicInfo.TrainingType != NordicWalkingType {
+		t.Errorf("TrainingType = %q, want %q", nordicInfo.TrainingType, NordicWalkingType)
+	}
+}
+
+func TestFatigueShortensLongRun(t *testing.T) {
+	running := Running{
+		Training: Training{
+			TrainingType: RunningType,
+			Action:       20000,
+			LenStep:      LenStep,
+			Duration:     2 * time.Hour,
+			Weight:       75,
+		},
+	}
+
+	t.Run("fatigue", func(t *testing.T) {
+		tired := running
+		tired.FatigueFactor = 0.1
+
+		// Средняя длина шага 0.65 * (1 - 0.1 * 2 / 2) = 0.585 м, 20000 шагов — 11.7 км.
+		if got := tired.TrainingInfo().Distance; math.Abs(got-11.7) > 1e-9 {
+			t.Errorf("Distance = %v, want 11.7", got)
+		}
+		if got := tired.TrainingInfo().Speed; math.Abs(got-5.85) > 1e-9 {
+			t.Errorf("Speed = %v, want 5.85", got)
+		}
+	})
+
+	t.Run("no fatigue", func(t *testing.T) {
+		if got := running.effectiveLenStep(); got != LenStep {
+			t.Errorf("effectiveLenStep() = %v, want %v", got, LenStep)
+		}
+		if got := running.TrainingInfo().Distance; got != 13 {
+			t.Errorf("Distance = %v, want 13", got)
+		}
+	})
+}
@@ -0,0 +1,71 @@
+package main
+
+import "math"
+
+// metRange значение MET для скоростей до MaxSpeed включительно.
+type metRange struct {
+	MaxSpeed float64 // верхняя граница диапазона скорости в км/ч
+	MET      float64 // метаболический эквивалент нагрузки
+}
+
+// metTable значения MET по видам тренировок и диапазонам скорости
+// по данным Compendium of Physical Activities. Диапазоны отсортированы по возрастанию скорости.
+var metTable = map[string][]metRange{
+	WalkingType: {
+		{MaxSpeed: 3.2, MET: 2.0},
+		{MaxSpeed: 4.0, MET: 2.8},
+		{MaxSpeed: 4.8, MET: 3.0},
+		{MaxSpeed: 5.6, MET: 3.5},
+		{MaxSpeed: 6.4, MET: 4.3},
+		{MaxSpeed: 7.2, MET: 5.0},
+		{MaxSpeed: 8.0, MET: 7.0},
+		{MaxSpeed: math.Inf(1), MET: 8.3},
+	},
+	NordicWalkingType: {
+		{MaxSpeed: math.Inf(1), MET: 4.8},
+	},
+	RunningType: {
+		{MaxSpeed: 6.4, MET: 6.0},
+		{MaxSpeed: 8.0, MET: 8.3},
+		{MaxSpeed: 8.4, MET: 9.0},
+		{MaxSpeed: 9.7, MET: 9.8},
+		{MaxSpeed: 10.8, MET: 10.5},
+		{MaxSpeed: 11.3, MET: 11.0},
+		{MaxSpeed: 12.1, MET: 11.8},
+		{MaxSpeed: 12.9, MET: 12.3},
+		{MaxSpeed: 13.8, MET: 12.8},
+		{MaxSpeed: 14.5, MET: 14.5},
+		{MaxSpeed: 16.1, MET: 16.0},
+		{MaxSpeed: 17.7, MET: 19.0},
+		{MaxSpeed: 19.3, MET: 19.8},
+		{MaxSpeed: math.Inf(1), MET: 23.0},
+	},
+	StationaryBikeType: {
+		{MaxSpeed: math.Inf(1), MET: 6.8},
+	},
+	StrengthType: {
+		{MaxSpeed: math.Inf(1), MET: StrengthMET},
+	},
+	SwimmingType: {
+		{MaxSpeed: 2.0, MET: 5.8},
+		{MaxSpeed: 3.0, MET: 8.3},
+		{MaxSpeed: math.Inf(1), MET: 9.8},
+	},
+}
+
+// LookupMET возвращает метаболический эквивалент нагрузки (MET) для вида тренировки при скорости speedKmh в км/ч.
+// Вид тренировки приводится к каноническому виду с помощью NormalizeType.
+// Для неизвестного вида тренировки или отрицательной скорости возвращает 0.
+func LookupMET(trainingType string, speedKmh float64) float64 {
+	if speedKmh < 0 {
+		return 0
+	}
+
+	for _, speedRange := range metTable[NormalizeType(trainingType)] {
+		if speedKmh <= speedRange.MaxSpeed {
+			return speedRange.MET
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,565 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Метрики в этом файле считаются по InfoMessage, поэтому доступны для любой тренировки
+// через TrainingInfo() и учитывают переопределенные для каждого вида тренировки формулы.
+
+// Константы для оценки средней мощности.
+const (
+	WattsPerKcalPerMin   = 69.78 // количество ватт в одной килокалории в минуту
+	MechanicalEfficiency = 0.24  // доля потраченной энергии, которая переходит в механическую работу
+)
+
+// AveragePowerWatts возвращает оценку средней механической мощности за тренировку в ваттах.
+// Формула расчета:
+// потрачено_ккал / время_тренировки_в_минутах * 69.78 * 0.24
+func (i InfoMessage) AveragePowerWatts() float64 {
+	timeOfTrainingInMinutes := i.Duration.Minutes()
+
+	if timeOfTrainingInMinutes == 0 {
+		return 0
+	}
+
+	caloriesPerMinute := i.Calories / timeOfTrainingInMinutes
+
+	return caloriesPerMinute * WattsPerKcalPerMin * MechanicalEfficiency
+}
+
+// Пороговые скорости, при которых тренировка считается тяжелой, в км/ч.
+const (
+	RunningThresholdSpeed  = 12  // пороговая скорость бега
+	WalkingThresholdSpeed  = 6.5 // пороговая скорость ходьбы
+	SwimmingThresholdSpeed = 3   // пороговая скорость плавания
+)
+
+// thresholdSpeed возвращает пороговую скорость для типа тренировки или 0, если тип неизвестен.
+func thresholdSpeed(trainingType string) float64 {
+	switch NormalizeType(trainingType) {
+	case RunningType:
+		return RunningThresholdSpeed
+	case WalkingType, NordicWalkingType:
+		return WalkingThresholdSpeed
+	case SwimmingType:
+		return SwimmingThresholdSpeed
+	}
+
+	return 0
+}
+
+// intensity возвращает отношение средней скорости к пороговой скорости для типа тренировки.
+// Для неизвестного типа возвращает 0.
+func (i InfoMessage) intensity() float64 {
+	threshold := thresholdSpeed(i.TrainingType)
+
+	if threshold == 0 {
+		return 0
+	}
+
+	return i.Speed / threshold
+}
+
+// Константы для оценки воспринимаемой нагрузки по шкале RPE.
+const (
+	MinPerceivedExertion   = 1  // минимальная оценка нагрузки
+	MaxPerceivedExertion   = 10 // максимальная оценка нагрузки
+	ExertionIntensityScale = 7  // прибавка к оценке при скорости, равной пороговой
+	ExertionPerHour        = 1  // прибавка к оценке за каждый час тренировки
+)
+
+// PerceivedExertion возвращает приблизительную оценку воспринимаемой нагрузки (RPE) от 1 до 10.
+// Формула расчета:
+// 1 + 7 * средняя_скорость / пороговая_скорость + 1 * время_тренировки_в_часах
+// Тренировка на пороговой скорости длительностью час оценивается в 9 баллов,
+// тренировка без движения или неизвестного типа — в 1 балл плюс прибавка за продолжительность.
+func (i InfoMessage) PerceivedExertion() float64 {
+	exertion := MinPerceivedExertion + ExertionIntensityScale*i.intensity() + ExertionPerHour*i.Duration.Hours()
+
+	return math.Max(MinPerceivedExertion, math.Min(MaxPerceivedExertion, exertion))
+}
+
+// Константы для оценки доли углеводов и жиров в расходе энергии.
+const (
+	LowIntensityThreshold  = 0.5 // интенсивность, ниже которой доля углеводов минимальна
+	HighIntensityThreshold = 1.0 // интенсивность, выше которой доля углеводов максимальна
+	MinCarbFraction        = 0.3 // доля углеводов при низкой интенсивности
+	MaxCarbFraction        = 0.9 // доля углеводов при высокой интенсивности
+)
+
+// EnergySplit возвращает, сколько килокалорий пришлось на жиры и на углеводы.
+// Интенсивность — отношение средней скорости к пороговой скорости для типа тренировки.
+// При интенсивности до 0.5 на углеводы приходится 30% энергии, от 1.0 — 90%,
+// между порогами доля углеводов растет линейно. Сумма значений равна Calories.
+func (i InfoMessage) EnergySplit() (fatKcal, carbKcal float64) {
+	intensity := i.intensity()
+
+	carbFraction := MinCarbFraction
+	switch {
+	case intensity >= HighIntensityThreshold:
+		carbFraction = MaxCarbFraction
+	case intensity > LowIntensityThreshold:
+		carbFraction += (MaxCarbFraction - MinCarbFraction) *
+			(intensity - LowIntensityThreshold) / (HighIntensityThreshold - LowIntensityThreshold)
+	}
+
+	carbKcal = i.Calories * carbFraction
+	fatKcal = i.Calories - carbKcal
+
+	return fatKcal, carbKcal
+}
+
+// HoursInDay количество часов в сутках.
+const HoursInDay = 24
+
+// NetCalories возвращает, сколько килокалорий потрачено сверх базового обмена веществ за время тренировки.
+// Формула расчета:
+// потрачено_ккал - базовый_обмен_в_ккал_в_сутки * время_тренировки_в_часах / ч_в_сутках
+// Если BMR не указан, результат равен Calories. Результат не бывает отрицательным.
+func (i InfoMessage) NetCalories() float64 {
+	restingCalories := i.BMR * i.Duration.Hours() / HoursInDay
+
+	return math.Max(0, i.Calories-restingCalories)
+}
+
+// CaloriesForDistance возвращает, сколько килокалорий потребуется, чтобы преодолеть distanceKm км
+// с той же средней скоростью, что и на этой тренировке.
+// При постоянной скорости расход калорий пропорционален времени, а значит и дистанции.
+// Исключение — бег с надбавкой за длительный бег (Running.LongRunBonus): надбавка растет быстрее времени,
+// и для дистанций, которые сильно отличаются от дистанции тренировки, результат приблизительный.
+// Формула расчета:
+// потрачено_ккал / дистанция_в_км * целевая_дистанция_в_км
+// Для distanceKm <= 0 или тренировки без дистанции возвращает 0.
+func (i InfoMessage) CaloriesForDistance(distanceKm float64) float64 {
+	if distanceKm <= 0 || i.Distance == 0 {
+		return 0
+	}
+
+	return i.Calories / i.Distance * distanceKm
+}
+
+// Константы для оценки времени восстановления.
+const (
+	MinRecoveryCalories    = 150  // тренировки с меньшим расходом калорий не требуют восстановления
+	RecoveryHoursPerKcal   = 0.04 // часов восстановления на каждую потраченную килокалорию
+	RecoveryIntensityShift = 0.5  // множитель восстановления при нулевой интенсивности
+	MaxRecoveryHours       = 72   // максимальное рекомендуемое время восстановления в часах
+)
+
+// RecommendedRecovery возвращает рекомендуемое время отдыха перед следующей тяжелой тренировкой.
+// Формула расчета:
+// потрачено_ккал * 0.04 * (0.5 + интенсивность) часов, но не больше 72 часов,
+// где интенсивность — отношение средней скорости к пороговой скорости для типа тренировки.
+// Например, 500 ккал на пороговой скорости требуют 30 часов отдыха.
+// Для легких тренировок с расходом меньше 150 ккал возвращает 0.
+func (i InfoMessage) RecommendedRecovery() time.Duration {
+	if i.Calories < MinRecoveryCalories {
+		return 0
+	}
+
+	recoveryHours := i.Calories * RecoveryHoursPerKcal * (RecoveryIntensityShift + i.intensity())
+	recoveryHours = math.Min(recoveryHours, MaxRecoveryHours)
+
+	return time.Duration(recoveryHours * float64(time.Hour))
+}
+
+// Pace возвращает средний темп — время, за которое преодолевается 1 км.
+// Формула расчета:
+// продолжительность_тренировки / дистанция_в_км
+// Для тренировки без дистанции возвращает 0.
+func (i InfoMessage) Pace() time.Duration {
+	if i.Distance == 0 {
+		return 0
+	}
+
+	return time.Duration(float64(i.Duration) / i.Distance)
+}
+
+// CarEmissionGramsPerKm средний выброс CO2 легкового автомобиля в г на км.
+const CarEmissionGramsPerKm = 170
+
+// CO2SavedGramsVsCar возвращает, сколько граммов CO2 не попало в атмосферу благодаря тому,
+// что дистанция тренировки пройдена пешком или пробежкой, а не на автомобиле.
+// Формула расчета:
+// дистанция_в_км * 170
+// Считается только для бега и ходьбы, для остальных видов тренировок возвращает 0.
+func (i InfoMessage) CO2SavedGramsVsCar() float64 {
+	switch NormalizeType(i.TrainingType) {
+	case RunningType, WalkingType, NordicWalkingType:
+		return i.Distance * CarEmissionGramsPerKm
+	}
+
+	return 0
+}
+
+// IntensityFactor возвращает отношение средней скорости к пороговой скорости thresholdSpeed в км/ч,
+// заданной пользователем. Значение 1 означает тренировку на пороге.
+// Для thresholdSpeed <= 0 или тренировки без скорости, например силовой, возвращает 0.
+func (i InfoMessage) IntensityFactor(thresholdSpeed float64) float64 {
+	if thresholdSpeed <= 0 || i.Speed == 0 {
+		return 0
+	}
+
+	return i.Speed / thresholdSpeed
+}
+
+// Границы темповых зон как отношение темпа тренировки к пороговому темпу.
+// Чем больше отношение, тем медленнее тренировка.
+const (
+	PaceZone1Ratio = 1.29 // медленнее — зона 1, восстановительный бег
+	PaceZone2Ratio = 1.14 // медленнее — зона 2, легкий бег
+	PaceZone3Ratio = 1.06 // медленнее — зона 3, темповый бег
+	PaceZone4Ratio = 0.97 // медленнее — зона 4, пороговый бег, быстрее — зона 5, интервалы
+)
+
+// PaceZone возвращает номер темповой зоны от 1 до 5 для среднего темпа тренировки
+// относительно порогового темпа thresholdPace (время на 1 км).
+// Для thresholdPace <= 0 или тренировки без дистанции возвращает 0.
+func (i InfoMessage) PaceZone(thresholdPace time.Duration) int {
+	pace := i.Pace()
+
+	if thresholdPace <= 0 || pace == 0 {
+		return 0
+	}
+
+	ratio := float64(pace) / float64(thresholdPace)
+
+	switch {
+	case ratio > PaceZone1Ratio:
+		return 1
+	case ratio > PaceZone2Ratio:
+		return 2
+	case ratio > PaceZone3Ratio:
+		return 3
+	case ratio > PaceZone4Ratio:
+		return 4
+	}
+
+	return 5
+}
+
+// Константы для плана питья на длительной тренировке.
+const (
+	SweatMlPerKcal          = 1                // потеря жидкости в мл на каждую потраченную килокалорию
+	HydrationMinDuration    = time.Hour        // тренировки короче не требуют питья по ходу
+	HydrationInterval       = 20 * time.Minute // интервал между напоминаниями
+	HydrationRoundingVolume = 10               // объем в мл, до которого округляется порция
+)
+
+// EstimatedFluidLossMl возвращает оценку потери жидкости за тренировку в мл.
+// Формула расчета:
+// потрачено_ккал * 1
+func (i InfoMessage) EstimatedFluidLossMl() float64 {
+	return i.Calories * SweatMlPerKcal
+}
+
+// HydrationPlan возвращает напоминания о питье каждые 20 минут тренировки,
+// например "20 мин: ~250 мл". Порции восполняют оценку потери жидкости,
+// пропорциональную времени между напоминаниями, и округляются до 10 мл.
+// Для тренировок короче часа возвращает пустой план.
+func (i InfoMessage) HydrationPlan() []string {
+	if i.Duration < HydrationMinDuration {
+		return nil
+	}
+
+	portion := i.EstimatedFluidLossMl() * float64(HydrationInterval) / float64(i.Duration)
+	portion = math.Round(portion/HydrationRoundingVolume) * HydrationRoundingVolume
+
+	var plan []string
+	for at := HydrationInterval; at < i.Duration; at += HydrationInterval {
+		plan = append(plan, fmt.Sprintf("%v мин: ~%v мл", at.Minutes(), portion))
+	}
+
+	return plan
+}
+
+// CaloriesUncertainty относительная погрешность оценки калорий по видам тренировок.
+var CaloriesUncertainty = map[string]float64{
+	RunningType:        0.10,
+	WalkingType:        0.15,
+	NordicWalkingType:  0.20,
+	SwimmingType:       0.20,
+	StationaryBikeType: 0.10,
+	StrengthType:       0.25,
+}
+
+// DefaultCaloriesUncertainty относительная погрешность для видов тренировок, которых нет в CaloriesUncertainty.
+const DefaultCaloriesUncertainty = 0.15
+
+// CaloriesRange возвращает нижнюю и верхнюю границы оценки потраченных килокалорий
+// с учетом погрешности формулы для вида тренировки.
+// Формула расчета:
+// потрачено_ккал * (1 ± погрешность)
+func (i InfoMessage) CaloriesRange() (low, high float64) {
+	uncertainty, ok := CaloriesUncertainty[NormalizeType(i.TrainingType)]
+	if !ok {
+		uncertainty = DefaultCaloriesUncertainty
+	}
+
+	return i.Calories * (1 - uncertainty), i.Calories * (1 + uncertainty)
+}
+
+// Константы для расчета темпа с поправкой на уклон (GAP).
+const (
+	GAPUphillGradeMultiplier   = 3.3 // рост затрат на единицу уклона при подъеме
+	GAPDownhillGradeMultiplier = 1.8 // снижение затрат на единицу уклона при спуске
+	GAPMinCostFactor           = 0.8 // минимальный множитель затрат при спуске
+)
+
+// grade возвращает средний уклон тренировки как долю: набор_высоты_в_м / дистанция_в_м.
+// Для тренировки без дистанции возвращает 0.
+func (i InfoMessage) grade() float64 {
+	if i.Distance == 0 {
+		return 0
+	}
+
+	return i.ElevationGain / (i.Distance * MInKm)
+}
+
+// gradeAdjustedPace возвращает темп, эквивалентный темпу по ровной местности.
+// Формула расчета:
+// темп / множитель_затрат, где множитель_затрат равен 1 + 3.3 * уклон при подъеме
+// и 1 + 1.8 * уклон при спуске, но не меньше 0.8.
+func (i InfoMessage) gradeAdjustedPace() time.Duration {
+	grade := i.grade()
+
+	costFactor := 1 + GAPUphillGradeMultiplier*grade
+	if grade < 0 {
+		costFactor = math.Max(GAPMinCostFactor, 1+GAPDownhillGradeMultiplier*grade)
+	}
+
+	return time.Duration(float64(i.Pace()) / costFactor)
+}
+
+// DistanceForCalories возвращает, сколько км нужно преодолеть с той же средней скоростью,
+// чтобы потратить targetCalories килокалорий. Обратный расчет к CaloriesForDistance,
+// поэтому для бега с надбавкой за длительный бег результат так же приблизительный.
+// Формула расчета:
+// целевые_ккал / (потрачено_ккал / дистанция_в_км)
+// Для targetCalories <= 0 или тренировки без дистанции или расхода калорий возвращает 0.
+func (i InfoMessage) DistanceForCalories(targetCalories float64) float64 {
+	if targetCalories <= 0 || i.Distance == 0 || i.Calories == 0 {
+		return 0
+	}
+
+	caloriesPerKm := i.Calories / i.Distance
+
+	return targetCalories / caloriesPerKm
+}
+
+// Пороговые скорости для определения вида тренировки по скорости в км/ч.
+const (
+	GuessWalkingMaxSpeed = 7  // медленнее — ходьба
+	GuessRunningMaxSpeed = 20 // медленнее — бег, быстрее — велосипед
+)
+
+// CyclingType название езды на велосипеде. Отдельной структуры для нее нет,
+// название используется только при определении вида тренировки по скорости.
+const CyclingType = "Велосипед"
+
+// GuessType возвращает вероятный вид тренировки по средней скорости speedKmh в км/ч:
+// ходьба при скорости меньше 7 км/ч, бег — от 7 до 20 км/ч, велосипед — от 20 км/ч.
+// Для скорости <= 0 возвращает пустую строку.
+func GuessType(speedKmh float64) string {
+	switch {
+	case speedKmh <= 0:
+		return ""
+	case speedKmh < GuessWalkingMaxSpeed:
+		return WalkingType
+	case speedKmh < GuessRunningMaxSpeed:
+		return RunningType
+	}
+
+	return CyclingType
+}
+
+// EquivalentSteps возвращает количество шагов, эквивалентное тренировке.
+// Для бега и ходьбы это количество сделанных шагов, для остальных видов тренировок —
+// дистанция, деленная на длину шага LenStep. Для тренировок без дистанции возвращает 0.
+func (i InfoMessage) EquivalentSteps() int {
+	switch NormalizeType(i.TrainingType) {
+	case RunningType, WalkingType, NordicWalkingType:
+		return i.Action
+	}
+
+	return int(math.Round(i.Distance * MInKm / LenStep))
+}
+
+// CaloriesToMilestone возвращает, сколько килокалорий осталось потратить до следующего значения,
+// кратного milestone, например до следующих 100 ккал.
+// Если расход уже кратен milestone, следующей отметкой считается следующее кратное значение.
+// Для milestone <= 0 возвращает 0.
+func (i InfoMessage) CaloriesToMilestone(milestone float64) float64 {
+	if milestone <= 0 {
+		return 0
+	}
+
+	nextMilestone := (math.Floor(i.Calories/milestone) + 1) * milestone
+
+	return nextMilestone - i.Calories
+}
+
+// FoodCalories калорийность популярных продуктов в ккал на порцию.
+// Таблицу можно дополнить своими продуктами.
+var FoodCalories = map[string]float64{
+	"банан":               105,
+	"яблоко":              95,
+	"кусок пиццы":         285,
+	"шоколадный батончик": 250,
+	"стакан колы":         140,
+}
+
+// FoodEquivalent возвращает, скольким порциям каждого продукта из FoodCalories
+// соответствуют потраченные на тренировке килокалории.
+// Продукты с неположительной калорийностью не учитываются.
+func (i InfoMessage) FoodEquivalent() map[string]float64 {
+	equivalent := make(map[string]float64, len(FoodCalories))
+
+	for food, calories := range FoodCalories {
+		if calories <= 0 {
+			continue
+		}
+		equivalent[food] = i.Calories / calories
+	}
+
+	return equivalent
+}
+
+// RelativeEffort возвращает отношение потраченных килокалорий к расходу на тренировке reference,
+// например 1.2 означает 120% от обычной пробежки.
+// Если на тренировке reference калории не потрачены, возвращает 0.
+func (i InfoMessage) RelativeEffort(reference InfoMessage) float64 {
+	if reference.Calories == 0 {
+		return 0
+	}
+
+	return i.Calories / reference.Calories
+}
+
+// PowerToWeight возвращает отношение средней мощности к весу пользователя в Вт/кг.
+// Формула расчета:
+// средняя_мощность_в_ваттах / вес_спортсмена_в_кг
+// Если вес не указан или мощность нулевая, возвращает 0.
+func (i InfoMessage) PowerToWeight() float64 {
+	power := i.AveragePowerWatts()
+
+	if i.Weight <= 0 || power == 0 {
+		return 0
+	}
+
+	return power / i.Weight
+}
+
+// Константы для оценки порогового темпа.
+const (
+	RiegelExponent          = 1.06             // показатель степени в формуле Ригеля для времени на дистанции
+	ThresholdEffortDuration = time.Hour        // пороговый темп — темп, который можно держать около часа
+	ThresholdMinDuration    = 20 * time.Minute // более короткие тренировки не дают осмысленной оценки
+)
+
+// EstimateThresholdPace возвращает оценку порогового (лактатного) темпа — время на 1 км,
+// которое спортсмен может держать около часа, по среднему темпу тяжелой тренировки.
+// Оценка эвристическая: считается, что тренировка выполнена с максимальным равномерным усилием,
+// а темп пересчитывается на час по формуле Ригеля.
+// Формула расчета:
+// темп * (ThresholdEffortDuration / продолжительность_тренировки) ^ ((RiegelExponent - 1) / RiegelExponent)
+// Для тренировки короче ThresholdMinDuration или без дистанции возвращает 0.
+func (i InfoMessage) EstimateThresholdPace() time.Duration {
+	pace := i.Pace()
+
+	if i.Duration < ThresholdMinDuration || pace == 0 {
+		return 0
+	}
+
+	ratio := float64(ThresholdEffortDuration) / float64(i.Duration)
+
+	return time.Duration(float64(pace) * math.Pow(ratio, (RiegelExponent-1)/RiegelExponent))
+}
+
+// CaloriesPerHour возвращает расход калорий за час тренировки, чтобы сравнивать
+// интенсивность разных видов тренировок независимо от их продолжительности.
+// Формула расчета:
+// потрачено_ккал / время_тренировки_в_часах
+// Для тренировки с нулевой продолжительностью возвращает 0.
+func (i InfoMessage) CaloriesPerHour() float64 {
+	if i.Duration <= 0 {
+		return 0
+	}
+
+	return i.Calories / i.Duration.Hours()
+}
+
+// MileageContribution возвращает долю недельной цели по дистанции goalKm в км,
+// которую составляет дистанция этой тренировки, например 0.25 для 10 км при цели 40 км.
+// Формула расчета:
+// дистанция_в_км / цель_в_км
+// Для goalKm <= 0 возвращает 0.
+func (i InfoMessage) MileageContribution(goalKm float64) float64 {
+	if goalKm <= 0 {
+		return 0
+	}
+
+	return i.Distance / goalKm
+}
+
+// Константы уравнения ACSM для потребления кислорода при беге.
+const (
+	ACSMHorizontalCoefficient = 0.2 // мл O2 на кг на м горизонтального перемещения
+	ACSMVerticalCoefficient   = 0.9 // мл O2 на кг на м подъема
+	ACSMRestingVO2            = 3.5 // потребление кислорода в покое в мл/кг/мин
+)
+
+// EstimatedVO2 возвращает теоретическое потребление кислорода при средней скорости тренировки
+// в мл/кг/мин по уравнению ACSM для бега. Спуски не учитываются: уклон меньше нуля считается нулевым.
+// Формула расчета:
+// 0.2 * скорость_в_м_в_мин + 0.9 * скорость_в_м_в_мин * уклон + 3.5
+// Для тренировки с нулевой скоростью возвращает 0.
+func (i InfoMessage) EstimatedVO2() float64 {
+	if i.Speed == 0 {
+		return 0
+	}
+
+	speedInMetersPerMinute := i.Speed * MInKm / MinInHours
+
+	return ACSMHorizontalCoefficient*speedInMetersPerMinute +
+		ACSMVerticalCoefficient*speedInMetersPerMinute*math.Max(0, i.grade()) +
+		ACSMRestingVO2
+}
+
+// SuggestReclassification возвращает вид тренировки, определенный по средней скорости с помощью GuessType,
+// если он не совпадает с указанным видом, например бег для ходьбы со скоростью 12 км/ч.
+// Скандинавская ходьба считается ходьбой. Проверяются только бег и ходьба — у остальных видов
+// тренировок скорость не говорит о виде. Если вид соответствует скорости, возвращает false.
+func (i InfoMessage) SuggestReclassification() (string, bool) {
+	trainingType := NormalizeType(i.TrainingType)
+
+	switch trainingType {
+	case NordicWalkingType:
+		trainingType = WalkingType
+	case RunningType, WalkingType:
+	default:
+		return "", false
+	}
+
+	guessed := GuessType(i.Speed)
+	if guessed == "" || guessed == trainingType {
+		return "", false
+	}
+
+	return guessed, true
+}
+
+// FlatMetersPerElevationMeter количество м по ровной местности, эквивалентное 1 м набора высоты.
+const FlatMetersPerElevationMeter = 10
+
+// EquivalentFlatDistance возвращает дистанцию в км, эквивалентную тренировке по ровной местности:
+// каждые 10 м набора высоты добавляют 100 м дистанции. Спуски дистанцию не уменьшают.
+// Формула расчета:
+// дистанция_в_км + набор_высоты_в_м * FlatMetersPerElevationMeter / м_в_км
+// Без набора высоты возвращает дистанцию тренировки.
+func (i InfoMessage) EquivalentFlatDistance() float64 {
+	return i.Distance + math.Max(0, i.ElevationGain)*FlatMetersPerElevationMeter/MInKm
+}
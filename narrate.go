@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Language язык текстовых описаний тренировки.
+type Language string
+
+// Поддерживаемые языки.
+const (
+	LanguageRU Language = "ru"
+	LanguageEN Language = "en"
+)
+
+// russianPlural возвращает форму слова, согласованную с целым числом n:
+// one для 1, 21, 31..., few для 2-4, 22-24..., many для остальных.
+func russianPlural(n int, one, few, many string) string {
+	n %= 100
+	if n >= 11 && n <= 19 {
+		return many
+	}
+
+	switch n % 10 {
+	case 1:
+		return one
+	case 2, 3, 4:
+		return few
+	}
+
+	return many
+}
+
+// russianPluralFloat возвращает форму слова, согласованную с числом value.
+// Для дробных чисел используется форма few, например "2,5 километра".
+func russianPluralFloat(value float64, one, few, many string) string {
+	if value != math.Trunc(value) {
+		return few
+	}
+
+	return russianPlural(int(value), one, few, many)
+}
+
+// englishPlural возвращает one для value == 1 и many в остальных случаях.
+func englishPlural(value float64, one, many string) string {
+	if value == 1 {
+		return one
+	}
+
+	return many
+}
+
+// spokenNumber возвращает число, округленное до десятых, без лишних нулей, например "5" вместо "5.00".
+func (o FormatOptions) spokenNumber(value float64) string {
+	return o.replaceSeparator(strconv.FormatFloat(math.Round(value*10)/10, 'f', -1, 64))
+}
+
+// Narrate возвращает описание тренировки одним предложением для озвучивания
+// на языке из DefaultFormatOptions.
+func (i InfoMessage) Narrate() string {
+	return i.NarrateWith(DefaultFormatOptions)
+}
+
+// NarrateWith возвращает описание тренировки одним предложением для озвучивания на языке из opts,
+// например "Вы пробежали 5 километров за 30 минут и сожгли 298 килокалорий.".
+// Дистанция округляется до десятых, минуты и калории — до целых.
+func (i InfoMessage) NarrateWith(opts FormatOptions) string {
+	distance := math.Round(i.Distance*10) / 10
+	minutes := int(math.Round(i.Duration.Minutes()))
+	calories := int(math.Round(i.Calories))
+
+	if opts.Language == LanguageEN {
+		return i.narrateEN(opts, distance, minutes, calories)
+	}
+
+	return i.narrateRU(opts, distance, minutes, calories)
+}
+
+// narrateRU возвращает описание тренировки на русском языке.
+func (i InfoMessage) narrateRU(opts FormatOptions, distance float64, minutes, calories int) string {
+	duration := fmt.Sprintf("%d %s", minutes, russianPlural(minutes, "минуту", "минуты", "минут"))
+	burned := fmt.Sprintf("%d %s", calories, russianPlural(calories, "килокалорию", "килокалории", "килокалорий"))
+
+	verb := ""
+	switch NormalizeType(i.TrainingType) {
+	case RunningType:
+		verb = "пробежали"
+	case WalkingType, NordicWalkingType:
+		verb = "прошли"
+	case SwimmingType:
+		verb = "проплыли"
+	}
+
+	if verb == "" || distance == 0 {
+		return fmt.Sprintf("Вы тренировались %s и сожгли %s.", duration, burned)
+	}
+
+	return fmt.Sprintf("Вы %s %s %s за %s и сожгли %s.",
+		verb,
+		opts.spokenNumber(distance),
+		russianPluralFloat(distance, "километр", "километра", "километров"),
+		duration,
+		burned,
+	)
+}
+
+// narrateEN возвращает описание тренировки на английском языке.
+func (i InfoMessage) narrateEN(opts FormatOptions, distance float64, minutes, calories int) string {
+	duration := fmt.Sprintf("%d %s", minutes, englishPlural(float64(minutes), "minute", "minutes"))
+	burned := fmt.Sprintf("%d %s", calories, englishPlural(float64(calories), "calorie", "calories"))
+
+	verb := ""
+	switch NormalizeType(i.TrainingType) {
+	case RunningType:
+		verb = "ran"
+	case WalkingType, NordicWalkingType:
+		verb = "walked"
+	case SwimmingType:
+		verb = "swam"
+	}
+
+	if verb == "" || distance == 0 {
+		return fmt.Sprintf("You trained for %s and burned %s.", duration, burned)
+	}
+
+	return fmt.Sprintf("You %s %s %s in %s and burned %s.",
+		verb,
+		opts.spokenNumber(distance),
+		englishPlural(distance, "kilometer", "kilometers"),
+		duration,
+		burned,
+	)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+// CaloriesBarChart возвращает текстовую диаграмму потраченных килокалорий по типам тренировок:
+// по одной строке на тип в алфавитном порядке, самая длинная полоса занимает width символов.
+// Для width <= 0 или пустого списка возвращает пустую строку.
+func CaloriesBarChart(trainings []CaloriesCalculator, width int) string {
+	if width <= 0 || len(trainings) == 0 {
+		return ""
+	}
+
+	caloriesByType := CaloriesByType(trainings)
+
+	trainingTypes := make([]string, 0, len(caloriesByType))
+	labelWidth := 0
+	maxCalories := 0.0
+	for trainingType, calories := range caloriesByType {
+		trainingTypes = append(trainingTypes, trainingType)
+		if length := utf8.RuneCountInString(trainingType); length > labelWidth {
+			labelWidth = length
+		}
+		maxCalories = math.Max(maxCalories, calories)
+	}
+	sort.Strings(trainingTypes)
+
+	var chart strings.Builder
+	for _, trainingType := range trainingTypes {
+		calories := caloriesByType[trainingType]
+
+		barLength := 0
+		if maxCalories > 0 {
+			barLength = int(math.Round(calories / maxCalories * float64(width)))
+		}
+
+		fmt.Fprintf(&chart, "%-*s |%s %.2f\n", labelWidth, trainingType, strings.Repeat("#", barLength), calories)
+	}
+
+	return chart.String()
+}
+
+// Table возвращает таблицу тренировок с выровненными столбцами: тип, дистанция, скорость,
+// калории и продолжительность, а также итоговую строку.
+// Числа форматируются так же, как в InfoMessage.String().
+func Table(trainings []CaloriesCalculator) string {
+	var table strings.Builder
+	opts := DefaultFormatOptions
+
+	writer := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "Тип тренировки\tДистанция, км\tСр. скорость, км/ч\tПотрачено ккал\tДлительность, мин")
+
+	for _, training := range trainings {
+		info := training.TrainingInfo()
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+			info.TrainingType,
+			opts.formatNumber("%.2f", info.Distance),
+			opts.formatNumber("%.2f", info.Speed),
+			opts.formatNumber("%.2f", info.Calories),
+			opts.formatNumber("%v", info.Duration.Minutes()),
+		)
+	}
+
+	fmt.Fprintf(writer, "Итого\t%s\t\t%s\t%s\n",
+		opts.formatNumber("%.2f", TotalDistance(trainings)),
+		opts.formatNumber("%.2f", TotalCalories(trainings)),
+		opts.formatNumber("%v", TotalDuration(trainings).Minutes()),
+	)
+	writer.Flush()
+
+	return table.String()
+}
+
+// trainingEmojis эмодзи видов тренировок для EmojiSummary.
+var trainingEmojis = map[string]string{
+	RunningType:        "🏃",
+	WalkingType:        "🚶",
+	NordicWalkingType:  "🚶",
+	SwimmingType:       "🏊",
+	StationaryBikeType: "🚴",
+	StrengthType:       "🏋",
+}
+
+// Эмодзи, не зависящие от вида тренировки.
+const (
+	EmojiUnknownType = "🏅" // неизвестный вид тренировки
+	EmojiCalories    = "🔥"
+	EmojiDuration    = "⏱"
+)
+
+// EmojiSummary возвращает короткую строку для мессенджеров, например "🏃 5.3km 🔥298kcal ⏱30m".
+// Эмодзи выбирается по виду тренировки, дистанция не выводится, если она нулевая.
+// Дистанция округляется до десятых с разделителем дробной части из DefaultFormatOptions.
+func (i InfoMessage) EmojiSummary() string {
+	emoji, ok := trainingEmojis[NormalizeType(i.TrainingType)]
+	if !ok {
+		emoji = EmojiUnknownType
+	}
+
+	parts := []string{emoji}
+	if i.Distance != 0 {
+		parts = append(parts, DefaultFormatOptions.spokenNumber(i.Distance)+"km")
+	}
+	parts = append(parts,
+		fmt.Sprintf("%s%.0fkcal", EmojiCalories, i.Calories),
+		fmt.Sprintf("%s%.0fm", EmojiDuration, i.Duration.Minutes()),
+	)
+
+	return strings.Join(parts, " ")
+}
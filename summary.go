@@ -0,0 +1,570 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// DistanceByType возвращает суммарную дистанцию в км для каждого типа тренировки.
+// Типы тренировок приводятся к каноническому виду с помощью NormalizeType.
+// Для пустого списка возвращает пустую карту.
+func DistanceByType(trainings []CaloriesCalculator) map[string]float64 {
+	distances := make(map[string]float64)
+
+	for _, training := range trainings {
+		info := training.TrainingInfo()
+		distances[NormalizeType(info.TrainingType)] += info.Distance
+	}
+
+	return distances
+}
+
+// HasTag сообщает, отмечена ли тренировка меткой tag. Метки сравниваются с учетом регистра.
+func (t Training) HasTag(tag string) bool {
+	for _, trainingTag := range t.Tags {
+		if trainingTag == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FilterByTag возвращает тренировки, отмеченные меткой tag, в исходном порядке.
+// Метки сравниваются точно, с учетом регистра.
+func FilterByTag(trainings []CaloriesCalculator, tag string) []CaloriesCalculator {
+	var filtered []CaloriesCalculator
+
+	for _, training := range trainings {
+		if training.TrainingInfo().HasTag(tag) {
+			filtered = append(filtered, training)
+		}
+	}
+
+	return filtered
+}
+
+// FilterByDateRange возвращает тренировки, начатые в промежутке [from, to] включительно, в исходном порядке.
+// Тренировки без времени начала StartedAt не попадают в результат.
+func FilterByDateRange(trainings []CaloriesCalculator, from, to time.Time) []CaloriesCalculator {
+	var filtered []CaloriesCalculator
+
+	for _, training := range trainings {
+		startedAt := training.TrainingInfo().StartedAt
+		if startedAt.IsZero() || startedAt.Before(from) || startedAt.After(to) {
+			continue
+		}
+		filtered = append(filtered, training)
+	}
+
+	return filtered
+}
+
+// TotalTRIMP возвращает суммарную тренировочную нагрузку TRIMP, например за неделю.
+func TotalTRIMP(trainings []CaloriesCalculator, restHR, maxHR int) float64 {
+	total := 0.0
+
+	for _, training := range trainings {
+		total += training.TrainingInfo().TRIMP(restHR, maxHR)
+	}
+
+	return total
+}
+
+// SameSession сообщает, описывают ли t и other одну и ту же тренировку:
+// совпадают тип, продолжительность, количество повторов и вес,
+// а время начала — если оно указано у обеих тренировок.
+// Типы тренировок сравниваются после приведения к каноническому виду с помощью NormalizeType.
+func (t Training) SameSession(other Training) bool {
+	if NormalizeType(t.TrainingType) != NormalizeType(other.TrainingType) || t.Duration != other.Duration ||
+		t.Action != other.Action || t.Weight != other.Weight {
+		return false
+	}
+
+	if t.StartedAt.IsZero() || other.StartedAt.IsZero() {
+		return true
+	}
+
+	return t.StartedAt.Equal(other.StartedAt)
+}
+
+// Dedup возвращает тренировки без повторов, сохраняя порядок и первое вхождение каждой тренировки.
+// Повторы определяются с помощью SameSession.
+func Dedup(trainings []CaloriesCalculator) []CaloriesCalculator {
+	var unique []CaloriesCalculator
+	var seen []Training
+
+	for _, training := range trainings {
+		current := training.TrainingInfo().Training
+
+		duplicate := false
+		for _, previous := range seen {
+			if previous.SameSession(current) {
+				duplicate = true
+				break
+			}
+		}
+
+		if !duplicate {
+			seen = append(seen, current)
+			unique = append(unique, training)
+		}
+	}
+
+	return unique
+}
+
+// TotalDistance возвращает суммарную дистанцию тренировок в км.
+func TotalDistance(trainings []CaloriesCalculator) float64 {
+	total := 0.0
+
+	for _, training := range trainings {
+		total += training.TrainingInfo().Distance
+	}
+
+	return total
+}
+
+// WeekOverWeek возвращает изменение суммарной дистанции текущей недели относительно предыдущей в процентах.
+// Формула расчета:
+// (дистанция_текущей_недели - дистанция_предыдущей_недели) / дистанция_предыдущей_недели * 100
+// Если на предыдущей неделе дистанция нулевая, возвращает +Inf при ненулевой дистанции текущей недели
+// и 0, если обе дистанции нулевые. Проверить результат можно с помощью math.IsInf.
+func WeekOverWeek(current, previous []CaloriesCalculator) float64 {
+	currentDistance := TotalDistance(current)
+	previousDistance := TotalDistance(previous)
+
+	if previousDistance == 0 {
+		if currentDistance == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+
+	return (currentDistance - previousDistance) / previousDistance * 100
+}
+
+// CaloriesByType возвращает суммарное количество потраченных килокалорий для каждого типа тренировки.
+// Типы тренировок приводятся к каноническому виду с помощью NormalizeType.
+// Для пустого списка возвращает пустую карту.
+func CaloriesByType(trainings []CaloriesCalculator) map[string]float64 {
+	calories := make(map[string]float64)
+
+	for _, training := range trainings {
+		info := training.TrainingInfo()
+		calories[NormalizeType(info.TrainingType)] += info.Calories
+	}
+
+	return calories
+}
+
+// Названия частей дня для PartitionByDaypart.
+const (
+	DaypartMorning   = "morning"   // с 5:00 до 12:00
+	DaypartAfternoon = "afternoon" // с 12:00 до 17:00
+	DaypartEvening   = "evening"   // с 17:00 до 22:00
+	DaypartNight     = "night"     // с 22:00 до 5:00
+	DaypartUnknown   = "unknown"   // время начала тренировки не указано
+)
+
+// daypart возвращает часть дня, к которой относится время t по местному времени.
+func daypart(t time.Time) string {
+	switch hour := t.Local().Hour(); {
+	case hour >= 5 && hour < 12:
+		return DaypartMorning
+	case hour >= 12 && hour < 17:
+		return DaypartAfternoon
+	case hour >= 17 && hour < 22:
+		return DaypartEvening
+	}
+
+	return DaypartNight
+}
+
+// PartitionByDaypart распределяет тренировки по частям дня по местному времени начала.
+// Тренировки без StartedAt попадают в DaypartUnknown. Порядок тренировок внутри части дня сохраняется.
+func PartitionByDaypart(trainings []CaloriesCalculator) map[string][]CaloriesCalculator {
+	partitions := make(map[string][]CaloriesCalculator)
+
+	for _, training := range trainings {
+		startedAt := training.TrainingInfo().StartedAt
+
+		part := DaypartUnknown
+		if !startedAt.IsZero() {
+			part = daypart(startedAt)
+		}
+
+		partitions[part] = append(partitions[part], training)
+	}
+
+	return partitions
+}
+
+// TotalCalories возвращает суммарное количество потраченных килокалорий.
+func TotalCalories(trainings []CaloriesCalculator) float64 {
+	total := 0.0
+
+	for _, training := range trainings {
+		total += training.TrainingInfo().Calories
+	}
+
+	return total
+}
+
+// Heatmap возвращает суммарное количество потраченных килокалорий по дням для календаря активности.
+// Ключи — даты начала тренировок по местному времени в формате DateLayout.
+// Дней без тренировок и тренировок без StartedAt в карте нет.
+func Heatmap(trainings []CaloriesCalculator) map[string]float64 {
+	calories := make(map[string]float64)
+
+	for _, training := range trainings {
+		info := training.TrainingInfo()
+		if info.StartedAt.IsZero() {
+			continue
+		}
+		calories[info.StartedAt.Local().Format(DateLayout)] += info.Calories
+	}
+
+	return calories
+}
+
+// TotalDuration возвращает суммарную продолжительность тренировок.
+func TotalDuration(trainings []CaloriesCalculator) time.Duration {
+	var total time.Duration
+
+	for _, training := range trainings {
+		total += training.TrainingInfo().Duration
+	}
+
+	return total
+}
+
+// OverlapThreshold доля продолжительности более короткой тренировки, которая должна пересекаться
+// с другой тренировкой того же типа, чтобы они считались одной тренировкой.
+var OverlapThreshold = 0.5
+
+// overlaps сообщает, пересекаются ли по времени тренировки a и b одного типа
+// не меньше чем на OverlapThreshold продолжительности более короткой из них.
+func overlaps(a, b Training) bool {
+	if NormalizeType(a.TrainingType) != NormalizeType(b.TrainingType) ||
+		a.StartedAt.IsZero() || b.StartedAt.IsZero() {
+		return false
+	}
+
+	start := a.StartedAt
+	if b.StartedAt.After(start) {
+		start = b.StartedAt
+	}
+
+	end := a.StartedAt.Add(a.Duration)
+	if bEnd := b.StartedAt.Add(b.Duration); bEnd.Before(end) {
+		end = bEnd
+	}
+
+	shorter := a.Duration
+	if b.Duration < shorter {
+		shorter = b.Duration
+	}
+
+	overlap := end.Sub(start)
+
+	return overlap > 0 && float64(overlap) >= OverlapThreshold*float64(shorter)
+}
+
+// MergeOverlapping объединяет пересекающиеся по времени тренировки одного типа,
+// например записанные одновременно часами и телефоном.
+// Из пересекающихся тренировок остается самая длинная, при равной продолжительности — первая.
+// Тренировки без StartedAt остаются без изменений. Порядок тренировок сохраняется.
+func MergeOverlapping(trainings []CaloriesCalculator) []CaloriesCalculator {
+	sessions := make([]Training, len(trainings))
+	for i, training := range trainings {
+		sessions[i] = training.TrainingInfo().Training
+	}
+
+	var merged []CaloriesCalculator
+	for i, training := range trainings {
+		replaced := false
+		for j := range trainings {
+			if i == j || !overlaps(sessions[i], sessions[j]) {
+				continue
+			}
+
+			if sessions[j].Duration > sessions[i].Duration ||
+				(sessions[j].Duration == sessions[i].Duration && j < i) {
+				replaced = true
+				break
+			}
+		}
+
+		if !replaced {
+			merged = append(merged, training)
+		}
+	}
+
+	return merged
+}
+
+// CalorieShares возвращает долю каждого типа тренировки в суммарном расходе калорий.
+// Сумма долей равна 1. Для пустого списка или нулевого суммарного расхода возвращает пустую карту.
+func CalorieShares(trainings []CaloriesCalculator) map[string]float64 {
+	shares := make(map[string]float64)
+
+	total := TotalCalories(trainings)
+	if total == 0 {
+		return shares
+	}
+
+	for trainingType, calories := range CaloriesByType(trainings) {
+		shares[trainingType] = calories / total
+	}
+
+	return shares
+}
+
+// StepGoalProgress возвращает долю дневной цели goal по шагам, выполненную на тренировках,
+// с учетом эквивалентных шагов для всех видов тренировок (см. EquivalentSteps).
+// При перевыполнении цели результат больше 1. Для goal <= 0 возвращает 0.
+func StepGoalProgress(trainings []CaloriesCalculator, goal int) float64 {
+	if goal <= 0 {
+		return 0
+	}
+
+	steps := 0
+	for _, training := range trainings {
+		steps += training.TrainingInfo().EquivalentSteps()
+	}
+
+	return float64(steps) / float64(goal)
+}
+
+// TSSPerHourAtThreshold нагрузка TSS за час тренировки на пороговой скорости.
+const TSSPerHourAtThreshold = 100
+
+// WeeklyTSS возвращает суммарную тренировочную нагрузку TSS (Training Stress Score) тренировок,
+// например за неделю, при пороговой скорости thresholdSpeed в км/ч.
+// Формула расчета для каждой тренировки:
+// время_тренировки_в_часах * IntensityFactor² * 100
+// Час на пороговой скорости дает 100 баллов. Для пустого списка возвращает 0.
+func WeeklyTSS(trainings []CaloriesCalculator, thresholdSpeed float64) float64 {
+	total := 0.0
+
+	for _, training := range trainings {
+		info := training.TrainingInfo()
+		intensityFactor := info.IntensityFactor(thresholdSpeed)
+		total += info.Duration.Hours() * intensityFactor * intensityFactor * TSSPerHourAtThreshold
+	}
+
+	return total
+}
+
+// Константы для проверки рекомендаций ВОЗ по физической активности.
+const (
+	WHOModerateMET            = 3   // минимальный MET умеренной активности
+	WHOVigorousMET            = 6   // минимальный MET интенсивной активности
+	WHOModerateMinutesPerWeek = 150 // рекомендованные минуты умеренной активности в неделю
+	WHOVigorousMinutesPerWeek = 75  // рекомендованные минуты интенсивной активности в неделю
+)
+
+// MeetsWHOGuidelines сообщает, соответствуют ли тренировки за неделю рекомендациям ВОЗ:
+// не меньше 150 минут умеренной или 75 минут интенсивной активности либо их сочетание,
+// в котором минута интенсивной активности засчитывается за две минуты умеренной.
+// Интенсивность определяется по LookupMET для вида тренировки и средней скорости:
+// от 3 до 6 MET — умеренная активность, от 6 MET — интенсивная, меньше 3 MET не учитывается.
+func MeetsWHOGuidelines(trainings []CaloriesCalculator) bool {
+	moderateMinutes := 0.0
+
+	for _, training := range trainings {
+		info := training.TrainingInfo()
+		met := LookupMET(info.TrainingType, info.Speed)
+
+		switch {
+		case met >= WHOVigorousMET:
+			moderateMinutes += info.Duration.Minutes() * WHOModerateMinutesPerWeek / WHOVigorousMinutesPerWeek
+		case met >= WHOModerateMET:
+			moderateMinutes += info.Duration.Minutes()
+		}
+	}
+
+	return moderateMinutes >= WHOModerateMinutesPerWeek
+}
+
+// MaxPlanSessions максимальное количество тренировок в недельном плане — по одной в день.
+const MaxPlanSessions = DaysInWeek
+
+// scaleAction возвращает количество повторов, умноженное на factor и округленное.
+func scaleAction(action int, factor float64) int {
+	return int(math.Round(float64(action) * factor))
+}
+
+// scaleSession возвращает копию тренировки, в которой продолжительность и объем движения
+// (шаги, гребки, бассейны, подходы) умножены на factor, так что скорость не меняется.
+// Слайсы Tags и PoolSets копируются, поэтому копия не разделяет данные с исходной тренировкой.
+// Для неизвестного вида тренировки возвращает false.
+func scaleSession(training CaloriesCalculator, factor float64) (CaloriesCalculator, bool) {
+	scaleTraining := func(t Training) Training {
+		t.Duration = time.Duration(float64(t.Duration) * factor)
+		t.Action = scaleAction(t.Action, factor)
+		if t.Tags != nil {
+			t.Tags = append([]string(nil), t.Tags...)
+		}
+		return t
+	}
+
+	switch t := training.(type) {
+	case Running:
+		t.Training = scaleTraining(t.Training)
+		return t, true
+	case Walking:
+		t.Training = scaleTraining(t.Training)
+		return t, true
+	case NordicWalking:
+		t.Training = scaleTraining(t.Training)
+		return t, true
+	case Swimming:
+		t.Training = scaleTraining(t.Training)
+		t.CountPool = scaleAction(t.CountPool, factor)
+		poolSets := make([]PoolSet, len(t.PoolSets))
+		for i, set := range t.PoolSets {
+			poolSets[i] = PoolSet{LengthPool: set.LengthPool, CountPool: scaleAction(set.CountPool, factor)}
+		}
+		t.PoolSets = poolSets
+		return t, true
+	case StationaryBike:
+		t.Training = scaleTraining(t.Training)
+		return t, true
+	case Strength:
+		t.Training = scaleTraining(t.Training)
+		t.Sets = scaleAction(t.Sets, factor)
+		return t, true
+	}
+
+	return nil, false
+}
+
+// PlanWeek возвращает план тренировок на неделю с суммарным расходом около targetCalories ккал.
+// Каждая тренировка плана — копия prototype с той же скоростью, но другой продолжительностью.
+// Количество тренировок равно количеству тренировок prototype, нужных для цели, но не больше 7,
+// и цель делится между ними поровну. Сумма приблизительна: количество шагов и подходов округляется,
+// а у бега с надбавкой за длительный бег (Running.LongRunBonus) расход не пропорционален времени.
+// Для targetCalories <= 0, тренировки без расхода калорий или неизвестного вида возвращает nil.
+func PlanWeek(targetCalories float64, prototype CaloriesCalculator) []CaloriesCalculator {
+	prototypeCalories := prototype.Calories()
+
+	if targetCalories <= 0 || prototypeCalories <= 0 {
+		return nil
+	}
+
+	sessionsCount := int(math.Ceil(targetCalories / prototypeCalories))
+	if sessionsCount > MaxPlanSessions {
+		sessionsCount = MaxPlanSessions
+	}
+
+	factor := targetCalories / float64(sessionsCount) / prototypeCalories
+	plan := make([]CaloriesCalculator, sessionsCount)
+	for i := range plan {
+		session, ok := scaleSession(prototype, factor)
+		if !ok {
+			return nil
+		}
+		plan[i] = session
+	}
+
+	return plan
+}
+
+// NoBreakEven значение BreakEven, когда тренировки не тратят одинаковое количество калорий
+// ни при какой положительной продолжительности, например при одинаковом расходе в час.
+const NoBreakEven time.Duration = -1
+
+// caloriesLiner тренировка, расход калорий которой можно представить линейной функцией продолжительности
+// при той же интенсивности: постоянная часть intercept в ккал плюс расход rate в ккал в час.
+type caloriesLiner interface {
+	caloriesLine() (intercept, rate float64)
+}
+
+// proportionalCaloriesLine возвращает линию расхода калорий для тренировки, расход которой пропорционален
+// продолжительности: постоянной части нет, расход в час равен calories / продолжительность_в_часах.
+// Для нулевой продолжительности расход в час равен 0.
+func proportionalCaloriesLine(calories float64, duration time.Duration) (intercept, rate float64) {
+	if duration <= 0 {
+		return 0, 0
+	}
+
+	return 0, calories / duration.Hours()
+}
+
+// breakEven возвращает продолжительность, при которой тренировки a и b с их интенсивностью
+// тратят одинаковое количество калорий. Меняется только продолжительность: скорость, мощность,
+// подходы силовой тренировки и отдых между сериями плавания остаются прежними.
+// Формула расчета:
+// (постоянная_часть_b - постоянная_часть_a) / (расход_в_час_a - расход_в_час_b)
+// Если расход в час одинаковый, b неизвестного вида или точка пересечения не положительная,
+// возвращает NoBreakEven.
+func breakEven(a caloriesLiner, b CaloriesCalculator) time.Duration {
+	other, ok := b.(caloriesLiner)
+	if !ok {
+		return NoBreakEven
+	}
+
+	interceptA, rateA := a.caloriesLine()
+	interceptB, rateB := other.caloriesLine()
+
+	if rateA == rateB {
+		return NoBreakEven
+	}
+
+	hours := (interceptB - interceptA) / (rateA - rateB)
+	if hours <= 0 {
+		return NoBreakEven
+	}
+
+	return time.Duration(hours * float64(time.Hour))
+}
+
+// Aggregator накапливает суммарные показатели тренировок по мере их добавления,
+// чтобы не пересчитывать весь список при каждой новой тренировке.
+// Нулевое значение готово к использованию. Aggregator не предназначен для одновременного
+// использования из нескольких горутин.
+type Aggregator struct {
+	distance float64       // суммарная дистанция в км
+	calories float64       // суммарное количество потраченных килокалорий
+	duration time.Duration // суммарная продолжительность
+}
+
+// Add добавляет тренировку к суммарным показателям.
+func (a *Aggregator) Add(training CaloriesCalculator) {
+	info := training.TrainingInfo()
+
+	a.distance += info.Distance
+	a.calories += info.Calories
+	a.duration += info.Duration
+}
+
+// Snapshot возвращает суммарные показатели добавленных тренировок: дистанцию, калории и продолжительность.
+// Средняя скорость взвешена по времени, то есть равна суммарной дистанции, деленной на суммарное время,
+// как если бы тренировки шли одна за другой. Вид тренировки не заполняется.
+func (a *Aggregator) Snapshot() InfoMessage {
+	info := InfoMessage{
+		Training: Training{Duration: a.duration},
+		Distance: a.distance,
+		Calories: a.calories,
+	}
+
+	if hours := a.duration.Hours(); hours != 0 {
+		info.Speed = a.distance / hours
+	}
+
+	return info
+}
+
+// WeeklyDeficit возвращает энергетический баланс за неделю в ккал: сколько поступило с едой
+// за вычетом базового обмена веществ и расхода на тренировках.
+// Отрицательное значение — дефицит, положительное — избыток.
+// Формула расчета:
+// (ккал_в_сутки_с_едой - базовый_обмен_в_ккал_в_сутки) * дней_в_неделе - потрачено_ккал_на_тренировках
+// Считается, что trainings — тренировки за одну неделю, а потребление и базовый обмен одинаковы каждый день.
+// Повседневная активность вне тренировок не учитывается. Расход на тренировках включает
+// базовый обмен за время тренировки, поэтому он вычитается дважды; без него расход дает NetCalories.
+func WeeklyDeficit(trainings []CaloriesCalculator, dailyIntakeKcal, bmrKcal float64) float64 {
+	return (dailyIntakeKcal-bmrKcal)*DaysInWeek - TotalCalories(trainings)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// EarthRadiusM средний радиус Земли в м.
+const EarthRadiusM = 6371000
+
+// Point точка GPS-трека.
+type Point struct {
+	Lat  float64   // широта в градусах
+	Lon  float64   // долгота в градусах
+	Elev float64   // высота над уровнем моря в м
+	Time time.Time // время прохождения точки
+}
+
+// Track GPS-трек тренировки — последовательность точек в порядке прохождения.
+type Track struct {
+	Points []Point
+}
+
+// haversine возвращает расстояние между двумя точками по поверхности Земли в м.
+// Формула расчета:
+// 2 * радиус_Земли * asin(sqrt(sin²(Δширота/2) + cos(широта1) * cos(широта2) * sin²(Δдолгота/2)))
+func haversine(from, to Point) float64 {
+	lat1 := from.Lat * math.Pi / 180
+	lat2 := to.Lat * math.Pi / 180
+	deltaLat := lat2 - lat1
+	deltaLon := (to.Lon - from.Lon) * math.Pi / 180
+
+	a := math.Pow(math.Sin(deltaLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(deltaLon/2), 2)
+
+	return 2 * EarthRadiusM * math.Asin(math.Sqrt(a))
+}
+
+// distance возвращает длину трека в м.
+func (t Track) distance() float64 {
+	distance := 0.0
+	for i := 1; i < len(t.Points); i++ {
+		distance += haversine(t.Points[i-1], t.Points[i])
+	}
+
+	return distance
+}
+
+// TrainingFromTrack возвращает тренировку Бег, построенную по GPS-треку.
+// Дистанция считается по формуле гаверсинусов, продолжительность — по времени первой и последней точек,
+// а количество шагов — как дистанция, деленная на длину шага LenStep.
+// Время начала тренировки — время первой точки, набор высоты — разница высот последней и первой точек.
+func TrainingFromTrack(track Track, weight float64) (CaloriesCalculator, error) {
+	if len(track.Points) < 2 {
+		return nil, errors.New("трек должен содержать не менее двух точек")
+	}
+
+	if weight <= 0 {
+		return nil, ErrInvalidWeight
+	}
+
+	first, last := track.Points[0], track.Points[len(track.Points)-1]
+	duration := last.Time.Sub(first.Time)
+
+	if duration <= 0 {
+		return nil, errors.New("время последней точки трека должно быть позже времени первой")
+	}
+
+	return Running{
+		Training: Training{
+			TrainingType:  RunningType,
+			Action:        int(math.Round(track.distance() / LenStep)),
+			LenStep:       LenStep,
+			Duration:      duration,
+			Weight:        weight,
+			StartedAt:     first.Time,
+			ElevationGain: last.Elev - first.Elev,
+		},
+	}, nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Ошибки проверки тренировки.
+var (
+	ErrInvalidWeight     = errors.New("вес пользователя должен быть больше нуля")
+	ErrSessionTooShort   = errors.New("тренировка слишком короткая")
+	ErrSemanticsMismatch = errors.New("метод Training вызван для тренировки с собственными формулами")
+	ErrStepModelMismatch = errors.New("длина шага не соответствует виду тренировки")
+)
+
+// StrictMode включает проверку того, что методы Training не вызываются вместо переопределенных.
+// Например, s.Training.TrainingInfo() для плавания считает скорость по гребкам, а не по бассейну,
+// и возвращает 0 калорий. В режиме StrictMode такие вызовы вызывают панику с ErrSemanticsMismatch.
+var StrictMode = false
+
+// checkSemantics вызывает панику с ErrSemanticsMismatch, если включен StrictMode,
+// а метод method из Training вызван для тренировки вида, у которого есть собственная структура.
+func (t Training) checkSemantics(method string) {
+	if !StrictMode {
+		return
+	}
+
+	switch trainingType := NormalizeType(t.TrainingType); trainingType {
+	case RunningType, WalkingType, NordicWalkingType, SwimmingType, StationaryBikeType, StrengthType:
+		panic(fmt.Errorf("%w: Training.%s() для тренировки %q, вызовите метод %s() у структуры этого вида",
+			ErrSemanticsMismatch, method, trainingType, method))
+	}
+}
+
+// MinSessionDuration минимальная продолжительность тренировки. Более короткие тренировки
+// считаются случайно записанными и не проходят проверку в Validate.
+var MinSessionDuration = 10 * time.Second
+
+// Validate проверяет, что по тренировке можно посчитать калории.
+// Вес должен быть больше нуля для всех видов тренировок. Формулы для бега, ходьбы и плавания умножают на вес,
+// а на велотренажере и при силовой тренировке калории считаются и без него, но незаполненный вес
+// все равно считается ошибкой: от него зависят другие показатели, например PowerToWeight.
+// Тренировки короче MinSessionDuration отклоняются с ошибкой ErrSessionTooShort.
+func (t Training) Validate() error {
+	if t.Weight <= 0 {
+		return fmt.Errorf("%w: указано %v кг", ErrInvalidWeight, t.Weight)
+	}
+
+	if t.Duration < MinSessionDuration {
+		return fmt.Errorf("%w: %v при минимуме %v", ErrSessionTooShort, t.Duration, MinSessionDuration)
+	}
+
+	return nil
+}
+
+// SafeCalories возвращает количество потраченных килокалорий или ошибку, если тренировка заполнена неверно.
+func SafeCalories(training CaloriesCalculator) (float64, error) {
+	if err := training.TrainingInfo().Validate(); err != nil {
+		return 0, err
+	}
+
+	return training.Calories(), nil
+}
+
+// SafeReadData возвращает информацию о проведенной тренировке или ошибку, если тренировка заполнена неверно.
+func SafeReadData(training CaloriesCalculator) (string, error) {
+	if err := training.TrainingInfo().Validate(); err != nil {
+		return "", err
+	}
+
+	return ReadData(training), nil
+}
+
+// expectedLenSteps ожидаемая длина шага или гребка в м для видов тренировок, дистанция которых
+// считается по количеству повторов.
+var expectedLenSteps = map[string]float64{
+	RunningType:       LenStep,
+	WalkingType:       LenStep,
+	NordicWalkingType: LenStep,
+	SwimmingType:      SwimmingLenStep,
+}
+
+// StepModelTolerance допустимое относительное отклонение LenStep от ожидаемой для вида тренировки
+// длины шага в ValidateStepModel, например 0.3 — на 30% в любую сторону.
+var StepModelTolerance = 0.3
+
+// ValidateStepModel проверяет, что длина шага LenStep соответствует виду тренировки:
+// например, для бега указана длина шага, а не длина гребка SwimmingLenStep.
+// Отклонение больше StepModelTolerance от ожидаемой длины возвращает ошибку ErrStepModelMismatch.
+// Для видов тренировок, дистанция которых не считается по шагам, возвращает nil.
+func (t Training) ValidateStepModel() error {
+	expected, ok := expectedLenSteps[NormalizeType(t.TrainingType)]
+	if !ok {
+		return nil
+	}
+
+	if math.Abs(t.LenStep-expected) > expected*StepModelTolerance {
+		return fmt.Errorf("%w: %q с длиной шага %v м при ожидаемой %v м",
+			ErrStepModelMismatch, t.TrainingType, t.LenStep, expected)
+	}
+
+	return nil
+}
+
+// ValidateStepModel проверяет длину гребка LenStep для плавания в одном бассейне.
+// Если заданы PoolSets, дистанция считается по сериям, LenStep не используется, и проверка не нужна.
+// Это переопределенный метод ValidateStepModel() из Training.
+func (s Swimming) ValidateStepModel() error {
+	if len(s.PoolSets) != 0 {
+		return nil
+	}
+
+	return s.Training.ValidateStepModel()
+}
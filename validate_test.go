@@ -0,0 +1,31 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateZeroWeightRunning(t *testing.T) {
+	running := Running{
+		Training: Training{
+			TrainingType: RunningType,
+			Action:       5000,
+			LenStep:      LenStep,
+			Duration:     30 * time.Minute,
+		},
+	}
+
+	if err := running.Validate(); !errors.Is(err, ErrInvalidWeight) {
+		t.Errorf("Validate() = %v, want %v", err, ErrInvalidWeight)
+	}
+
+	if _, err := SafeCalories(running); !errors.Is(err, ErrInvalidWeight) {
+		t.Errorf("SafeCalories() error = %v, want %v", err, ErrInvalidWeight)
+	}
+
+	running.Weight = 75
+	if err := running.Validate(); err != nil {
+		t.Errorf("Validate() with weight = %v, want nil", err)
+	}
+}